@@ -0,0 +1,363 @@
+// Package arrow converts Dec128 to and from the decimal128 representations of Apache Arrow and Apache Parquet without depending on their libraries.
+//
+// Both formats store a decimal as a two's complement unscaled integer with the precision and scale defined by the schema.
+// Arrow uses 16 little-endian bytes, Parquet uses big-endian FIXED_LEN_BYTE_ARRAY values or INT32/INT64 physical types for small precisions.
+package arrow
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jokruger/dec128"
+	"github.com/jokruger/dec128/state"
+	"github.com/jokruger/dec128/uint128"
+)
+
+const (
+	// MaxPrecision is the maximum precision of a decimal128 column.
+	MaxPrecision = 38
+
+	// MaxPrecisionInt32 is the maximum precision of a decimal stored as Parquet INT32.
+	MaxPrecisionInt32 = 9
+
+	// MaxPrecisionInt64 is the maximum precision of a decimal stored as Parquet INT64.
+	MaxPrecisionInt64 = 18
+
+	// Size is the number of bytes of an Arrow decimal128 value.
+	Size = 16
+)
+
+// FixedLength returns the minimal Parquet FIXED_LEN_BYTE_ARRAY length for the given precision, or -1 if the precision is out of range.
+func FixedLength(precision int) int {
+	if precision < 1 || precision > MaxPrecision {
+		return -1
+	}
+	// smallest n with 10^precision - 1 < 2^(8n-1)
+	n := 1
+	for uint128.Max.Rsh(uint(129-8*n)).Compare(dec128.Pow10Uint128[precision]) < 0 {
+		n++
+	}
+	return n
+}
+
+// PutArrow writes d as an Arrow decimal128(precision, scale) value into the first 16 bytes of b.
+// It returns an error if d is NaN, has more digits after the decimal point than scale, or does not fit into precision.
+func PutArrow(b []byte, d dec128.Dec128, precision int, scale int) error {
+	if len(b) < Size {
+		return io.ErrShortBuffer
+	}
+	u, err := unscaled(d, precision, scale)
+	if err != nil {
+		return err
+	}
+	u.PutBytes(b)
+	return nil
+}
+
+// FromArrow creates a new Dec128 from the first 16 bytes of b holding an Arrow decimal128 value with the given scale.
+// Scales above dec128.MaxScale are accepted if the extra digits are zeros.
+// In case of errors, it returns NaN with the corresponding error.
+func FromArrow(b []byte, scale int) dec128.Dec128 {
+	if len(b) < Size {
+		return dec128.NaN(state.NotEnoughBytes)
+	}
+	return fromUnscaled(uint128.FromBytes([16]byte(b)), scale)
+}
+
+// PutParquet writes d as a Parquet FIXED_LEN_BYTE_ARRAY decimal(precision, scale) value of len(b) bytes into b.
+// It returns an error if d is NaN, has more digits after the decimal point than scale, or does not fit into precision or len(b) bytes.
+func PutParquet(b []byte, d dec128.Dec128, precision int, scale int) error {
+	if len(b) == 0 {
+		return io.ErrShortBuffer
+	}
+
+	u, err := unscaled(d, precision, scale)
+	if err != nil {
+		return err
+	}
+
+	tmp := u.BytesBigEndian()
+	n := len(b)
+	if n < Size {
+		// the dropped bytes must be pure sign extension
+		ext := byte(0)
+		if u.Hi>>63 == 1 {
+			ext = 0xff
+		}
+		for _, c := range tmp[:Size-n] {
+			if c != ext {
+				return state.Overflow.Error()
+			}
+		}
+		if (tmp[Size-n]^ext)&0x80 != 0 {
+			return state.Overflow.Error()
+		}
+		copy(b, tmp[Size-n:])
+		return nil
+	}
+
+	ext := byte(0)
+	if u.Hi>>63 == 1 {
+		ext = 0xff
+	}
+	for i := range n - Size {
+		b[i] = ext
+	}
+	copy(b[n-Size:], tmp[:])
+
+	return nil
+}
+
+// FromParquet creates a new Dec128 from a Parquet FIXED_LEN_BYTE_ARRAY (or BYTE_ARRAY) decimal value with the given scale.
+// Scales above dec128.MaxScale are accepted if the extra digits are zeros.
+// In case of errors, it returns NaN with the corresponding error.
+func FromParquet(b []byte, scale int) dec128.Dec128 {
+	n := len(b)
+	if n == 0 {
+		return dec128.NaN(state.NotEnoughBytes)
+	}
+
+	ext := byte(0)
+	if b[0]&0x80 != 0 {
+		ext = 0xff
+	}
+
+	// sign extend or shrink to 16 bytes
+	tmp := [Size]byte{}
+	if n > Size {
+		for _, c := range b[:n-Size] {
+			if c != ext {
+				return dec128.NaN(state.Overflow)
+			}
+		}
+		if (b[n-Size]^ext)&0x80 != 0 {
+			return dec128.NaN(state.Overflow)
+		}
+		copy(tmp[:], b[n-Size:])
+	} else {
+		for i := range Size - n {
+			tmp[i] = ext
+		}
+		copy(tmp[Size-n:], b)
+	}
+
+	return fromUnscaled(uint128.FromBytesBigEndian(tmp), scale)
+}
+
+// ToParquetInt32 returns d as a Parquet INT32 decimal(precision, scale) value.
+// It returns an error if precision is above MaxPrecisionInt32, d is NaN, has more digits after the decimal point than scale, or does not fit into precision.
+func ToParquetInt32(d dec128.Dec128, precision int, scale int) (int32, error) {
+	if precision > MaxPrecisionInt32 {
+		return 0, state.InvalidFormat.Error()
+	}
+	u, err := unscaled(d, precision, scale)
+	if err != nil {
+		return 0, err
+	}
+	return int32(u.Lo), nil
+}
+
+// FromParquetInt32 creates a new Dec128 from a Parquet INT32 decimal value with the given scale.
+// In case of errors, it returns NaN with the corresponding error.
+func FromParquetInt32(v int32, scale int) dec128.Dec128 {
+	return FromParquetInt64(int64(v), scale)
+}
+
+// ToParquetInt64 returns d as a Parquet INT64 decimal(precision, scale) value.
+// It returns an error if precision is above MaxPrecisionInt64, d is NaN, has more digits after the decimal point than scale, or does not fit into precision.
+func ToParquetInt64(d dec128.Dec128, precision int, scale int) (int64, error) {
+	if precision > MaxPrecisionInt64 {
+		return 0, state.InvalidFormat.Error()
+	}
+	u, err := unscaled(d, precision, scale)
+	if err != nil {
+		return 0, err
+	}
+	return int64(u.Lo), nil
+}
+
+// FromParquetInt64 creates a new Dec128 from a Parquet INT64 decimal value with the given scale.
+// In case of errors, it returns NaN with the corresponding error.
+func FromParquetInt64(v int64, scale int) dec128.Dec128 {
+	if scale >= 0 && scale <= int(dec128.MaxScale) {
+		return dec128.DecodeFromInt64(v, uint8(scale))
+	}
+	u := uint128.FromUint64(uint64(v))
+	if v < 0 {
+		u.Hi = ^uint64(0)
+	}
+	return fromUnscaled(u, scale)
+}
+
+// EncodeArrow writes src as Arrow decimal128(precision, scale) values into dst, which must hold 16 bytes per value.
+// It stops at the first value that cannot be encoded and returns an error with its index.
+func EncodeArrow(dst []byte, src []dec128.Dec128, precision int, scale int) error {
+	if len(dst) < len(src)*Size {
+		return io.ErrShortBuffer
+	}
+	for i, d := range src {
+		if err := PutArrow(dst[i*Size:], d, precision, scale); err != nil {
+			return indexError(i, err)
+		}
+	}
+	return nil
+}
+
+// DecodeArrow fills dst with Arrow decimal128 values with the given scale from src, which must hold 16 bytes per value.
+// It stops at the first value that cannot be decoded and returns an error with its index.
+func DecodeArrow(dst []dec128.Dec128, src []byte, scale int) error {
+	if len(src) < len(dst)*Size {
+		return io.ErrShortBuffer
+	}
+	for i := range dst {
+		dst[i] = FromArrow(src[i*Size:], scale)
+		if dst[i].IsNaN() {
+			return indexError(i, dst[i].ErrorDetails())
+		}
+	}
+	return nil
+}
+
+// EncodeParquet writes src as Parquet FIXED_LEN_BYTE_ARRAY decimal(precision, scale) values of width bytes each into dst.
+// It stops at the first value that cannot be encoded and returns an error with its index.
+func EncodeParquet(dst []byte, src []dec128.Dec128, width int, precision int, scale int) error {
+	if width <= 0 || len(dst) < len(src)*width {
+		return io.ErrShortBuffer
+	}
+	for i, d := range src {
+		if err := PutParquet(dst[i*width:(i+1)*width], d, precision, scale); err != nil {
+			return indexError(i, err)
+		}
+	}
+	return nil
+}
+
+// DecodeParquet fills dst with Parquet FIXED_LEN_BYTE_ARRAY decimal values of width bytes each with the given scale from src.
+// It stops at the first value that cannot be decoded and returns an error with its index.
+func DecodeParquet(dst []dec128.Dec128, src []byte, width int, scale int) error {
+	if width <= 0 || len(src) < len(dst)*width {
+		return io.ErrShortBuffer
+	}
+	for i := range dst {
+		dst[i] = FromParquet(src[i*width:(i+1)*width], scale)
+		if dst[i].IsNaN() {
+			return indexError(i, dst[i].ErrorDetails())
+		}
+	}
+	return nil
+}
+
+// EncodeParquetInt32 writes src as Parquet INT32 decimal(precision, scale) values into dst.
+// It stops at the first value that cannot be encoded and returns an error with its index.
+func EncodeParquetInt32(dst []int32, src []dec128.Dec128, precision int, scale int) error {
+	if len(dst) < len(src) {
+		return io.ErrShortBuffer
+	}
+	for i, d := range src {
+		v, err := ToParquetInt32(d, precision, scale)
+		if err != nil {
+			return indexError(i, err)
+		}
+		dst[i] = v
+	}
+	return nil
+}
+
+// DecodeParquetInt32 fills dst with Parquet INT32 decimal values with the given scale from src.
+// It stops at the first value that cannot be decoded and returns an error with its index.
+func DecodeParquetInt32(dst []dec128.Dec128, src []int32, scale int) error {
+	if len(src) < len(dst) {
+		return io.ErrShortBuffer
+	}
+	for i := range dst {
+		dst[i] = FromParquetInt32(src[i], scale)
+		if dst[i].IsNaN() {
+			return indexError(i, dst[i].ErrorDetails())
+		}
+	}
+	return nil
+}
+
+// EncodeParquetInt64 writes src as Parquet INT64 decimal(precision, scale) values into dst.
+// It stops at the first value that cannot be encoded and returns an error with its index.
+func EncodeParquetInt64(dst []int64, src []dec128.Dec128, precision int, scale int) error {
+	if len(dst) < len(src) {
+		return io.ErrShortBuffer
+	}
+	for i, d := range src {
+		v, err := ToParquetInt64(d, precision, scale)
+		if err != nil {
+			return indexError(i, err)
+		}
+		dst[i] = v
+	}
+	return nil
+}
+
+// DecodeParquetInt64 fills dst with Parquet INT64 decimal values with the given scale from src.
+// It stops at the first value that cannot be decoded and returns an error with its index.
+func DecodeParquetInt64(dst []dec128.Dec128, src []int64, scale int) error {
+	if len(src) < len(dst) {
+		return io.ErrShortBuffer
+	}
+	for i := range dst {
+		dst[i] = FromParquetInt64(src[i], scale)
+		if dst[i].IsNaN() {
+			return indexError(i, dst[i].ErrorDetails())
+		}
+	}
+	return nil
+}
+
+// unscaled returns d as a two's complement unscaled integer at the given scale.
+func unscaled(d dec128.Dec128, precision int, scale int) (uint128.Uint128, error) {
+	switch {
+	case precision < 1 || precision > MaxPrecision || scale < 0 || scale > precision:
+		return uint128.Zero, state.InvalidFormat.Error()
+	case d.IsNaN():
+		return uint128.Zero, d.ErrorDetails()
+	}
+
+	c := d.Canonical()
+	if int(c.Scale()) > scale {
+		return uint128.Zero, state.RescaleToLowerScale.Error()
+	}
+
+	u, s := c.Coefficient().Mul(dec128.Pow10Uint128[scale-int(c.Scale())])
+	if s >= state.Error || u.Compare(dec128.Pow10Uint128[precision]) >= 0 {
+		return uint128.Zero, state.Overflow.Error()
+	}
+
+	if c.IsNegative() {
+		u = uint128.SubUnsafe(uint128.Zero, u)
+	}
+
+	return u, nil
+}
+
+// fromUnscaled creates a new Dec128 from a two's complement unscaled integer and scale.
+func fromUnscaled(u uint128.Uint128, scale int) dec128.Dec128 {
+	if scale < 0 || scale > MaxPrecision {
+		return dec128.NaN(state.ScaleOutOfRange)
+	}
+
+	neg := u.Hi>>63 == 1
+	if neg {
+		u = uint128.SubUnsafe(uint128.Zero, u)
+	}
+
+	for scale > int(dec128.MaxScale) {
+		q, r, _ := u.QuoRem64(10)
+		if r != 0 {
+			return dec128.NaN(state.ScaleOutOfRange)
+		}
+		u = q
+		scale--
+	}
+
+	return dec128.New(u, uint8(scale), neg)
+}
+
+func indexError(i int, err error) error {
+	return fmt.Errorf("value %d: %w", i, err)
+}
@@ -0,0 +1,227 @@
+package arrow
+
+import (
+	"encoding/hex"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/jokruger/dec128"
+	"github.com/jokruger/dec128/state"
+	"github.com/jokruger/dec128/uint128"
+)
+
+func TestFixedLength(t *testing.T) {
+	testCases := [...][2]int{
+		{0, -1}, {1, 1}, {2, 1}, {3, 2}, {9, 4}, {10, 5}, {18, 8}, {19, 9}, {38, 16}, {39, -1},
+	}
+
+	for _, tc := range testCases {
+		if n := FixedLength(tc[0]); n != tc[1] {
+			t.Errorf("FixedLength(%d): expected %d, got %d", tc[0], tc[1], n)
+		}
+	}
+}
+
+func TestArrow(t *testing.T) {
+	type testCase struct {
+		i string
+		p int
+		s int
+		a string // Arrow little-endian bytes
+		q string // Parquet big-endian bytes of FixedLength(p)
+	}
+
+	testCases := [...]testCase{
+		{"0", 5, 2, "00000000000000000000000000000000", "000000"},
+		{"123.45", 5, 2, "39300000000000000000000000000000", "003039"},
+		{"-123.45", 5, 2, "c7cfffffffffffffffffffffffffffff", "ffcfc7"},
+		{"-1", 38, 0, "ffffffffffffffffffffffffffffffff", "ffffffffffffffffffffffffffffffff"},
+		{"1.5", 9, 4, "983a0000000000000000000000000000", "00003a98"},
+		{"99999999999999999999999999999999999999", 38, 0, "ffffffff3f228a097ac4865aa84c3b4b", "4b3b4ca85a86c47a098a223fffffffff"},
+		{"-0.0000000000000000001", 38, 30, "001889b7e8ffffffffffffffffffffff", "ffffffffffffffffffffffe8b7891800"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.i, func(t *testing.T) {
+			d := dec128.FromString(tc.i)
+
+			a := make([]byte, Size)
+			if err := PutArrow(a, d, tc.p, tc.s); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s := hex.EncodeToString(a); s != tc.a {
+				t.Errorf("expected %s, got %s", tc.a, s)
+			}
+			if r := FromArrow(a, tc.s); !r.Equal(d) {
+				t.Errorf("expected %s, got %s", d.String(), r.String())
+			}
+
+			q := make([]byte, FixedLength(tc.p))
+			if err := PutParquet(q, d, tc.p, tc.s); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s := hex.EncodeToString(q); s != tc.q {
+				t.Errorf("expected %s, got %s", tc.q, s)
+			}
+			if r := FromParquet(q, tc.s); !r.Equal(d) {
+				t.Errorf("expected %s, got %s", d.String(), r.String())
+			}
+
+			// wider arrays are sign extended
+			w := make([]byte, 20)
+			if err := PutParquet(w, d, tc.p, tc.s); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if r := FromParquet(w, tc.s); !r.Equal(d) {
+				t.Errorf("expected %s, got %s", d.String(), r.String())
+			}
+		})
+	}
+}
+
+func TestArrowErrors(t *testing.T) {
+	type testCase struct {
+		i string
+		p int
+		s int
+		e error
+	}
+
+	testCases := [...]testCase{
+		{"abc", 10, 2, state.InvalidFormat.Error()},
+		{"1.234", 10, 2, state.RescaleToLowerScale.Error()},
+		{"1000", 5, 2, state.Overflow.Error()},
+		{"-1000", 5, 2, state.Overflow.Error()},
+		{"1", 0, 0, state.InvalidFormat.Error()},
+		{"1", 39, 0, state.InvalidFormat.Error()},
+		{"1", 5, 6, state.InvalidFormat.Error()},
+	}
+
+	for _, tc := range testCases {
+		d := dec128.FromString(tc.i)
+		if err := PutArrow(make([]byte, Size), d, tc.p, tc.s); err != tc.e {
+			t.Errorf("%s: expected error %v, got %v", tc.i, tc.e, err)
+		}
+	}
+
+	if err := PutArrow(make([]byte, Size-1), dec128.Zero, 10, 0); err != io.ErrShortBuffer {
+		t.Errorf("expected error %v, got %v", io.ErrShortBuffer, err)
+	}
+
+	// fits the precision but not the array length
+	if err := PutParquet(make([]byte, 2), dec128.FromString("40000"), 5, 0); err != state.Overflow.Error() {
+		t.Errorf("expected error %v, got %v", state.Overflow.Error(), err)
+	}
+
+	if d := FromArrow(make([]byte, Size-1), 0); d.ErrorDetails() != state.NotEnoughBytes.Error() {
+		t.Errorf("expected error %v, got %v", state.NotEnoughBytes.Error(), d.ErrorDetails())
+	}
+
+	// 1 with scale 38 has no exact representation
+	b := dec128.Pow10Uint128[0].Bytes()
+	if d := FromArrow(b[:], 38); d.ErrorDetails() != state.ScaleOutOfRange.Error() {
+		t.Errorf("expected error %v, got %v", state.ScaleOutOfRange.Error(), d.ErrorDetails())
+	}
+
+	// -2^127 is the smallest value of 16 bytes, 2^127 needs 17 bytes
+	w, _ := hex.DecodeString("ff80000000000000000000000000000000")
+	if d := FromParquet(w, 0); d.String() != "-170141183460469231731687303715884105728" {
+		t.Errorf("unexpected value %s", d.String())
+	}
+	w, _ = hex.DecodeString("0080000000000000000000000000000000")
+	if d := FromParquet(w, 0); d.ErrorDetails() != state.Overflow.Error() {
+		t.Errorf("expected error %v, got %v", state.Overflow.Error(), d.ErrorDetails())
+	}
+}
+
+func TestParquetInt(t *testing.T) {
+	d := dec128.FromString("-12345.67")
+
+	v32, err := ToParquetInt32(d, 9, 3)
+	if err != nil || v32 != -12345670 {
+		t.Errorf("expected -12345670, got %d (%v)", v32, err)
+	}
+	if r := FromParquetInt32(v32, 3); !r.Equal(d) {
+		t.Errorf("expected %s, got %s", d.String(), r.String())
+	}
+
+	v64, err := ToParquetInt64(d, 18, 10)
+	if err != nil || v64 != -123456700000000 {
+		t.Errorf("expected -123456700000000, got %d (%v)", v64, err)
+	}
+	if r := FromParquetInt64(v64, 10); !r.Equal(d) {
+		t.Errorf("expected %s, got %s", d.String(), r.String())
+	}
+	if r := FromParquetInt64(-1234567, 25); r.ErrorDetails() != state.ScaleOutOfRange.Error() {
+		t.Errorf("expected error %v, got %v", state.ScaleOutOfRange.Error(), r.ErrorDetails())
+	}
+	if r := FromParquetInt64(-100000000, 27); r.String() != "-0.0000000000000000001" {
+		t.Errorf("expected -0.0000000000000000001, got %s", r.String())
+	}
+
+	if _, err := ToParquetInt32(d, 10, 2); err != state.InvalidFormat.Error() {
+		t.Errorf("expected error %v, got %v", state.InvalidFormat.Error(), err)
+	}
+	if _, err := ToParquetInt64(d, 18, 15); err != state.Overflow.Error() {
+		t.Errorf("expected error %v, got %v", state.Overflow.Error(), err)
+	}
+}
+
+func TestSlices(t *testing.T) {
+	const p, s = 20, 6
+
+	src := make([]dec128.Dec128, 100)
+	for i := range src {
+		src[i] = dec128.New(uint128.FromUint64(rand.Uint64N(dec128.Pow10Uint64[11])), uint8(rand.IntN(s+1)), rand.IntN(2) == 0)
+	}
+
+	dst := make([]dec128.Dec128, len(src))
+
+	a := make([]byte, len(src)*Size)
+	if err := EncodeArrow(a, src, p, s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := DecodeArrow(dst, a, s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkSlice(t, src, dst)
+
+	w := FixedLength(p)
+	q := make([]byte, len(src)*w)
+	if err := EncodeParquet(q, src, w, p, s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := DecodeParquet(dst, q, w, s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkSlice(t, src, dst)
+
+	v := make([]int64, len(src))
+	if err := EncodeParquetInt64(v, src, 18, s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := DecodeParquetInt64(dst, v, s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkSlice(t, src, dst)
+
+	src[42] = dec128.FromString("0.0000001")
+	err := EncodeArrow(a, src, p, s)
+	if !errors.Is(err, state.RescaleToLowerScale.Error()) || err.Error() != "value 42: "+state.RescaleToLowerScale.Error().Error() {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := EncodeParquetInt32(make([]int32, 1), src, 9, s); err != io.ErrShortBuffer {
+		t.Errorf("expected error %v, got %v", io.ErrShortBuffer, err)
+	}
+}
+
+func checkSlice(t *testing.T, src, dst []dec128.Dec128) {
+	t.Helper()
+	for i := range src {
+		if !src[i].Equal(dst[i]) {
+			t.Errorf("%d: expected %s, got %s", i, src[i].String(), dst[i].String())
+		}
+	}
+}
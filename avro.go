@@ -0,0 +1,123 @@
+package dec128
+
+import (
+	"github.com/jokruger/dec128/state"
+	"github.com/jokruger/dec128/uint128"
+)
+
+// AvroDecimalMaxSize is the maximum number of bytes of a minimal-length Avro decimal produced by AppendAvroDecimal.
+const AvroDecimalMaxSize = 17
+
+// AppendAvroDecimal appends the Dec128 as an Avro decimal logical type value with the given schema scale to buf and returns the extended buffer.
+// The value is the big-endian two's complement unscaled integer in the minimal number of bytes, as used by the Avro bytes type.
+// It returns an error if the Dec128 is NaN or has more digits after the decimal point than scale.
+func (d Dec128) AppendAvroDecimal(buf []byte, scale uint8) ([]byte, error) {
+	b, n, err := d.avroDecimal(scale)
+	if err != nil {
+		return buf, err
+	}
+	return append(buf, b[len(b)-n:]...), nil
+}
+
+// AppendAvroDecimalFixed appends the Dec128 as an Avro decimal logical type value of the fixed type with the given size and schema scale to buf and returns the extended buffer.
+// It returns an error if the Dec128 is NaN, has more digits after the decimal point than scale, or does not fit into size bytes.
+func (d Dec128) AppendAvroDecimalFixed(buf []byte, size int, scale uint8) ([]byte, error) {
+	b, n, err := d.avroDecimal(scale)
+	if err != nil {
+		return buf, err
+	}
+	if n > size {
+		return buf, state.Overflow.Error()
+	}
+
+	ext := byte(0)
+	if b[0]&0x80 != 0 {
+		ext = 0xff
+	}
+	for range size - n {
+		buf = append(buf, ext)
+	}
+
+	return append(buf, b[len(b)-n:]...), nil
+}
+
+// DecodeAvroDecimal creates a new Dec128 from an Avro decimal logical type value (bytes or fixed) with the given schema scale.
+// The scale of the result is the schema scale.
+// Schema scales above MaxScale are accepted if the extra digits are zeros.
+// In case of errors, it returns NaN with the corresponding error.
+func DecodeAvroDecimal(b []byte, scale uint8) Dec128 {
+	n := len(b)
+	if n == 0 {
+		return Dec128{state: state.NotEnoughBytes}
+	}
+
+	ext := byte(0)
+	if b[0]&0x80 != 0 {
+		ext = 0xff
+	}
+
+	// sign extend or shrink to 16 bytes
+	tmp := [16]byte{}
+	if n > 16 {
+		for _, c := range b[:n-16] {
+			if c != ext {
+				return Dec128{state: state.Overflow}
+			}
+		}
+		copy(tmp[:], b[n-16:])
+	} else {
+		for i := range 16 - n {
+			tmp[i] = ext
+		}
+		copy(tmp[16-n:], b)
+	}
+
+	coef := uint128.FromBytesBigEndian(tmp)
+	st := state.Default
+	if ext != 0 {
+		// the magnitude of -2^128 does not fit into the coefficient
+		if coef.IsZero() {
+			return Dec128{state: state.Overflow}
+		}
+		coef = uint128.SubUnsafe(uint128.Zero, coef)
+		st = state.Neg
+	}
+
+	for ; scale > MaxScale; scale-- {
+		q, r, _ := coef.QuoRem64(10)
+		if r != 0 {
+			return Dec128{state: state.ScaleOutOfRange}
+		}
+		coef = q
+	}
+
+	return Dec128{coef: coef, scale: scale, state: st}
+}
+
+// avroDecimal returns the big-endian two's complement unscaled integer of d sign extended to 17 bytes and its minimal length.
+func (d Dec128) avroDecimal(scale uint8) ([AvroDecimalMaxSize]byte, int, error) {
+	b := [AvroDecimalMaxSize]byte{}
+
+	if d.state >= state.Error {
+		return b, 0, d.state.Error()
+	}
+
+	coef, s := d.impliedCoef(scale)
+	if s >= state.Error {
+		return b, 0, s.Error()
+	}
+
+	if d.state == state.Neg && !coef.IsZero() {
+		b[0] = 0xff
+		coef = uint128.SubUnsafe(uint128.Zero, coef)
+	}
+	coef.PutBytesBigEndian(b[1:])
+
+	// drop leading bytes that only repeat the sign
+	i := 0
+	for i < AvroDecimalMaxSize-1 && b[i] == b[0] && (b[i+1]^b[0])&0x80 == 0 {
+		i++
+	}
+
+	return b, AvroDecimalMaxSize - i, nil
+}
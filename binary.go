@@ -76,6 +76,7 @@ func (d Dec128) EncodeBinary(buf []byte) (int, error) {
 }
 
 // DecodeBinary decodes binary representation of Dec128 from buf. It returns an error if buf is too small, otherwise the number of bytes consumed from buf.
+// Both the original layout and the varint layout of EncodeBinaryV2 are accepted.
 func (d *Dec128) DecodeBinary(buf []byte) (int, error) {
 	sz := len(buf)
 	if sz == 0 {
@@ -84,6 +85,10 @@ func (d *Dec128) DecodeBinary(buf []byte) (int, error) {
 
 	flags := buf[0]
 
+	if flags&binaryV2 != 0 {
+		return d.decodeBinaryV2(buf)
+	}
+
 	// Determine how many extra bytes to read.
 	hiPresent := int((flags >> 7) & 1)  // 1 if coef.Hi is present, else 0
 	loPresent := int((flags >> 6) & 1)  // 1 if coef.Lo is present, else 0
@@ -130,7 +135,7 @@ func (d Dec128) MarshalBinary() ([]byte, error) {
 	return buf[:n], nil
 }
 
-// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface. It accepts both binary layouts.
 func (d *Dec128) UnmarshalBinary(data []byte) error {
 	n, err := d.DecodeBinary(data)
 	if err != nil {
@@ -173,10 +178,10 @@ func (d Dec128) WriteBinary(w io.Writer) error {
 	return err
 }
 
-// ReadBinary reads the binary representation of Dec128 from r.
+// ReadBinary reads the binary representation of Dec128 from r. It accepts both binary layouts.
 func (d *Dec128) ReadBinary(r io.Reader) error {
-	// Use one fixed buffer of 18 bytes.
-	var buf [18]byte
+	// Use one fixed buffer large enough for both layouts.
+	var buf [MaxBytesV2]byte
 
 	// First, read the flag byte.
 	if _, err := io.ReadFull(r, buf[:1]); err != nil {
@@ -184,6 +189,22 @@ func (d *Dec128) ReadBinary(r io.Reader) error {
 	}
 	flags := buf[0]
 
+	if flags&binaryV2 != 0 {
+		// Read the varint coefficient byte by byte up to its last byte.
+		n := 1
+		for buf[n-1]&0x80 != 0 || n == 1 {
+			if n > MaxBytesV2-1 {
+				return state.InvalidFormat.Error()
+			}
+			if _, err := io.ReadFull(r, buf[n:n+1]); err != nil {
+				return err
+			}
+			n++
+		}
+		_, err := d.decodeBinaryV2(buf[:n])
+		return err
+	}
+
 	// Determine how many extra bytes to read.
 	hiPresent := int((flags >> 7) & 1)  // 1 if coef.Hi is present, else 0
 	loPresent := int((flags >> 6) & 1)  // 1 if coef.Lo is present, else 0
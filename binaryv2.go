@@ -0,0 +1,132 @@
+package dec128
+
+import (
+	"io"
+
+	"github.com/jokruger/dec128/state"
+	"github.com/jokruger/dec128/uint128"
+)
+
+// MaxBytesV2 is the maximum number of bytes of the varint binary layout: flags byte and a 19-byte LEB128 coefficient.
+const MaxBytesV2 = 1 + 19
+
+// Flags of the varint binary layout.
+// The version bit is never set by the original layout, whose flags byte holds a state code below 16 in the low 5 bits.
+// The scale is split into the low 3 bits (bits 0-2) and the high 2 bits (bits 5-6).
+const (
+	binaryV2    = 0b00010000
+	binaryV2Neg = 0b00001000
+)
+
+// BinarySizeV2 returns the number of bytes required to encode this instance of Dec128 with EncodeBinaryV2.
+func (d Dec128) BinarySizeV2() int {
+	if d.state >= state.Error {
+		return 1
+	}
+
+	sz := 2
+	for u := d.coef.Rsh(7); !u.IsZero(); u = u.Rsh(7) {
+		sz++
+	}
+
+	return sz
+}
+
+// EncodeBinaryV2 encodes the Dec128 into buf using the compact varint layout: a flags byte holding the version, sign and scale,
+// followed by the coefficient as an unsigned LEB128 varint (e.g. 19.99 takes 3 bytes).
+// NaN is encoded as a single byte, the same as with EncodeBinary.
+// The result can be decoded with DecodeBinary, UnmarshalBinary and ReadBinary.
+// It returns an error if buf is too small, otherwise the number of bytes written into buf.
+func (d Dec128) EncodeBinaryV2(buf []byte) (int, error) {
+	if len(buf) == 0 {
+		return 0, io.ErrShortBuffer
+	}
+
+	if d.state >= state.Error {
+		buf[0] = byte(d.state)
+		return 1, nil
+	}
+
+	flags := byte(binaryV2) | d.scale&0b111 | d.scale>>3<<5
+	if d.state == state.Neg && !d.coef.IsZero() {
+		flags |= binaryV2Neg
+	}
+	buf[0] = flags
+
+	pos := 1
+	u := d.coef
+	for {
+		if pos >= len(buf) {
+			return pos, io.ErrShortBuffer
+		}
+		b := byte(u.Lo & 0x7f)
+		u = u.Rsh(7)
+		if u.IsZero() {
+			buf[pos] = b
+			return pos + 1, nil
+		}
+		buf[pos] = b | 0x80
+		pos++
+	}
+}
+
+// AppendBinaryV2 appends the varint binary layout of Dec128 to the end of buf and returns the updated slice.
+func (d Dec128) AppendBinaryV2(buf []byte) ([]byte, error) {
+	var tmp [MaxBytesV2]byte
+	n, err := d.EncodeBinaryV2(tmp[:])
+	if err != nil {
+		return buf, err
+	}
+	return append(buf, tmp[:n]...), nil
+}
+
+// WriteBinaryV2 writes the varint binary layout of Dec128 to w.
+func (d Dec128) WriteBinaryV2(w io.Writer) error {
+	var buf [MaxBytesV2]byte
+	n, err := d.EncodeBinaryV2(buf[:])
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf[:n])
+	return err
+}
+
+// decodeBinaryV2 decodes the varint binary layout from buf.
+// called only when the version bit of the flags byte is set
+func (d *Dec128) decodeBinaryV2(buf []byte) (int, error) {
+	flags := buf[0]
+
+	scale := flags&0b111 | (flags>>5)<<3
+	if scale > MaxScale {
+		return 1, state.InvalidFormat.Error()
+	}
+
+	var coef uint128.Uint128
+	pos := 1
+	for i := 0; ; i++ {
+		if pos >= len(buf) {
+			return pos, io.ErrShortBuffer
+		}
+		b := buf[pos]
+		pos++
+
+		// the 19th byte holds the top 2 bits
+		if i == MaxBytesV2-2 && b > 0b11 {
+			return pos, state.InvalidFormat.Error()
+		}
+		coef = coef.Or(uint128.FromUint64(uint64(b & 0x7f)).Lsh(uint(7 * i)))
+
+		if b&0x80 == 0 {
+			break
+		}
+	}
+
+	d.coef = coef
+	d.scale = scale
+	d.state = state.Default
+	if flags&binaryV2Neg != 0 && !coef.IsZero() {
+		d.state = state.Neg
+	}
+
+	return pos, nil
+}
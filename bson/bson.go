@@ -0,0 +1,131 @@
+// Package bson converts Dec128 to and from the BSON Decimal128 type (IEEE 754-2008 decimal128 in BID format) without depending on a MongoDB driver.
+package bson
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/jokruger/dec128"
+	"github.com/jokruger/dec128/internal/bid"
+	"github.com/jokruger/dec128/state"
+	"github.com/jokruger/dec128/uint128"
+)
+
+// TypeDecimal128 is the BSON element type of Decimal128 values.
+const TypeDecimal128 = byte(0x13)
+
+// Size is the number of bytes of a BSON Decimal128 value.
+const Size = 16
+
+// Config controls the conversion of BSON Decimal128 values that do not fit into Dec128.
+type Config struct {
+	// Round rounds values with more than dec128.MaxScale digits after the decimal point half to even.
+	// If not set, such values are decoded as NaN with the scale out of range error.
+	Round bool
+}
+
+// Default is the configuration used by the package level functions. It rejects values that need rounding.
+var Default = Config{}
+
+// ToBSONDecimal128 returns d as the high and low 64-bit words of a BSON Decimal128.
+// NaN is encoded as quiet NaN.
+// It returns an error if d has more than 34 significant digits and cannot be represented exactly.
+func ToBSONDecimal128(d dec128.Dec128) (uint64, uint64, error) {
+	b, err := d.ToIEEEDecimal128BID()
+	if err != nil {
+		return 0, 0, err
+	}
+	return binary.BigEndian.Uint64(b[:8]), binary.BigEndian.Uint64(b[8:]), nil
+}
+
+// FromBSONDecimal128 creates a new Dec128 from the high and low 64-bit words of a BSON Decimal128 using the Default configuration.
+// In case of errors, it returns NaN with the corresponding error.
+func FromBSONDecimal128(hi uint64, lo uint64) dec128.Dec128 {
+	return Default.FromBSONDecimal128(hi, lo)
+}
+
+// FromBSONDecimal128 creates a new Dec128 from the high and low 64-bit words of a BSON Decimal128.
+// NaN is decoded as NaN and infinity as NaN with overflow error.
+// In case of errors, it returns NaN with the corresponding error.
+func (c Config) FromBSONDecimal128(hi uint64, lo uint64) dec128.Dec128 {
+	neg, coef, exp, s := bid.Split(uint128.Uint128{Lo: lo, Hi: hi})
+	if s >= state.Error {
+		return dec128.NaN(s)
+	}
+
+	if c.Round && -exp > int(dec128.MaxScale) {
+		coef = roundHalfEven(coef, -exp-int(dec128.MaxScale))
+		exp = -int(dec128.MaxScale)
+	}
+
+	return dec128.FromCoefficient(neg, coef, exp)
+}
+
+// AppendBSONValue appends the 16-byte little-endian BSON Decimal128 representation of d to buf.
+func AppendBSONValue(buf []byte, d dec128.Dec128) ([]byte, error) {
+	hi, lo, err := ToBSONDecimal128(d)
+	if err != nil {
+		return buf, err
+	}
+	buf = binary.LittleEndian.AppendUint64(buf, lo)
+	return binary.LittleEndian.AppendUint64(buf, hi), nil
+}
+
+// MarshalBSONValue returns the BSON element type and the value bytes of d.
+// The results match the MarshalBSONValue method of the MongoDB driver's ValueMarshaler interface.
+func MarshalBSONValue(d dec128.Dec128) (byte, []byte, error) {
+	b, err := AppendBSONValue(make([]byte, 0, Size), d)
+	if err != nil {
+		return 0, nil, err
+	}
+	return TypeDecimal128, b, nil
+}
+
+// UnmarshalBSONValue decodes a BSON Decimal128 element value using the Default configuration.
+// It accepts the arguments of the UnmarshalBSONValue method of the MongoDB driver's ValueUnmarshaler interface.
+func UnmarshalBSONValue(t byte, b []byte) (dec128.Dec128, error) {
+	return Default.UnmarshalBSONValue(t, b)
+}
+
+// UnmarshalBSONValue decodes a BSON Decimal128 element value.
+// It returns an error if t is not TypeDecimal128, b is not 16 bytes long, or the value cannot be represented as Dec128.
+func (c Config) UnmarshalBSONValue(t byte, b []byte) (dec128.Dec128, error) {
+	switch {
+	case t != TypeDecimal128:
+		return dec128.Zero, state.InvalidFormat.Error()
+	case len(b) < Size:
+		return dec128.Zero, io.ErrShortBuffer
+	case len(b) > Size:
+		return dec128.Zero, state.InvalidFormat.Error()
+	}
+
+	d := c.FromBSONDecimal128(binary.LittleEndian.Uint64(b[8:]), binary.LittleEndian.Uint64(b[:8]))
+	if d.IsNaN() && d.ErrorDetails() != state.NaN.Error() {
+		return dec128.Zero, d.ErrorDetails()
+	}
+
+	return d, nil
+}
+
+// roundHalfEven returns coef / 10^n rounded half to even.
+func roundHalfEven(coef uint128.Uint128, n int) uint128.Uint128 {
+	if n >= len(dec128.Pow10Uint128) {
+		// coef < 10^34, so the result is always zero
+		return uint128.Zero
+	}
+
+	factor := dec128.Pow10Uint128[n]
+	q, r, _ := coef.QuoRem(factor)
+
+	// r < 10^38, so 2*r cannot overflow
+	switch r.Lsh(1).Compare(factor) {
+	case 1:
+		q, _ = q.Add64(1)
+	case 0:
+		if q.Lo&1 == 1 {
+			q, _ = q.Add64(1)
+		}
+	}
+
+	return q
+}
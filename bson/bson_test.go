@@ -0,0 +1,132 @@
+package bson
+
+import (
+	"encoding/hex"
+	"io"
+	"testing"
+
+	"github.com/jokruger/dec128"
+	"github.com/jokruger/dec128/state"
+)
+
+func TestBSONDecimal128(t *testing.T) {
+	type testCase struct {
+		i string
+		b string // little-endian value bytes as stored in BSON documents
+	}
+
+	testCases := [...]testCase{
+		{"0", "00000000000000000000000000004030"},
+		{"1", "01000000000000000000000000004030"},
+		{"-1", "010000000000000000000000000040b0"},
+		{"0.1", "01000000000000000000000000003e30"},
+		{"0.001234", "d2040000000000000000000000003430"},
+		{"-0.0000000000000000001", "01000000000000000000000000001ab0"},
+		{"1234567890123456789012345678901234", "f2af967ed05c82de3297ff6fde3c4030"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.i, func(t *testing.T) {
+			d := dec128.FromString(tc.i)
+
+			typ, b, err := MarshalBSONValue(d)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if typ != TypeDecimal128 {
+				t.Errorf("expected type %x, got %x", TypeDecimal128, typ)
+			}
+			if s := hex.EncodeToString(b); s != tc.b {
+				t.Errorf("expected %s, got %s", tc.b, s)
+			}
+
+			r, err := UnmarshalBSONValue(typ, b)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !r.Equal(d) {
+				t.Errorf("expected %s, got %s", d.String(), r.String())
+			}
+
+			hi, lo, err := ToBSONDecimal128(d)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if r := FromBSONDecimal128(hi, lo); !r.Equal(d) {
+				t.Errorf("expected %s, got %s", d.String(), r.String())
+			}
+		})
+	}
+}
+
+func TestBSONDecimal128Decode(t *testing.T) {
+	type testCase struct {
+		b string
+		s string
+		e error
+	}
+
+	// values from the BSON corpus that are not produced by the encoder
+	testCases := [...]testCase{
+		{"000000000000000000000000000040b0", "0", nil},                          // -0
+		{"01000000000000000000000000004630", "1000", nil},                       // 1E+3
+		{"0000000000000000000000000000007c", "NaN", nil},                        // NaN
+		{"00000000000000000000000000000078", "", state.Overflow.Error()},        // Inf
+		{"ffffffffffffffffffffffffffffff7b", "", state.Overflow.Error()},        // Inf with payload bits
+		{"01000000000000000000000000000000", "", state.ScaleOutOfRange.Error()}, // 1E-6176
+		{"0f000000000000000000000000001830", "", state.ScaleOutOfRange.Error()}, // 15E-20
+		{"01000000000000000000000000008e30", "", state.Overflow.Error()},        // 1E+39
+		{"00000000648e8d37c087adbe09ed4130", "0", nil},                          // 10^34 is not a canonical coefficient
+		{"ffffffffffffffffffffffffffff1f6c", "0", nil},                          // non-canonical coefficient of 2^113 or more
+		{"0000000000000000000000000000000000", "", state.InvalidFormat.Error()}, // too long
+		{"000000000000000000000000000000", "", io.ErrShortBuffer},               // too short
+	}
+
+	for _, tc := range testCases {
+		b, _ := hex.DecodeString(tc.b)
+		d, err := UnmarshalBSONValue(TypeDecimal128, b)
+		switch {
+		case tc.e != nil:
+			if err != tc.e {
+				t.Errorf("%s: expected error %v, got %v (%s)", tc.b, tc.e, err, d.String())
+			}
+		case err != nil:
+			t.Errorf("%s: unexpected error %v", tc.b, err)
+		case d.String() != tc.s:
+			t.Errorf("%s: expected %s, got %s", tc.b, tc.s, d.String())
+		}
+	}
+
+	if _, err := UnmarshalBSONValue(0x01, make([]byte, Size)); err == nil {
+		t.Errorf("expected error for wrong type, got nil")
+	}
+}
+
+func TestBSONDecimal128Round(t *testing.T) {
+	type testCase struct {
+		b string
+		s string
+	}
+
+	testCases := [...]testCase{
+		{"0f000000000000000000000000001830", "0.0000000000000000002"}, // 15E-20
+		{"19000000000000000000000000001830", "0.0000000000000000002"}, // 25E-20
+		{"fb000000000000000000000000001630", "0.0000000000000000003"}, // 251E-21
+	}
+
+	c := Config{Round: true}
+	for _, tc := range testCases {
+		b, _ := hex.DecodeString(tc.b)
+		d, err := c.UnmarshalBSONValue(TypeDecimal128, b)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tc.b, err)
+		}
+		if d.String() != tc.s || d.Scale() != dec128.MaxScale {
+			t.Errorf("%s: expected %s, got %s", tc.b, tc.s, d.StringFixed())
+		}
+	}
+
+	if d := c.FromBSONDecimal128(0xb000000000000000, 5); !d.IsZero() {
+		t.Errorf("expected 0, got %s", d.String())
+	}
+}
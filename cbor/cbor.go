@@ -0,0 +1,237 @@
+// Package cbor converts Dec128 to and from the CBOR decimal fraction (tag 4) defined in RFC 8949 without depending on a CBOR library.
+//
+// A decimal fraction is the tagged array [exponent, mantissa] with the value mantissa * 10^exponent.
+// Mantissas that do not fit into 64 bits are encoded as bignums (tags 2 and 3).
+package cbor
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/jokruger/dec128"
+	"github.com/jokruger/dec128/state"
+	"github.com/jokruger/dec128/uint128"
+)
+
+// CBOR tags used by the decimal fraction encoding.
+const (
+	TagPositiveBignum  = 2
+	TagNegativeBignum  = 3
+	TagDecimalFraction = 4
+)
+
+// MaxBytes is the maximum number of bytes of an encoded Dec128: tag, array header, exponent and a 16-byte bignum.
+const MaxBytes = 1 + 1 + 1 + 1 + 1 + 16
+
+// CBOR major types
+const (
+	majorUnsigned = 0
+	majorNegative = 1
+	majorBytes    = 2
+	majorArray    = 4
+	majorTag      = 6
+)
+
+// AppendDecimalFraction appends d as a CBOR decimal fraction to buf and returns the extended buffer.
+// It does not allocate if buf has enough capacity.
+// It returns an error if d is NaN, as decimal fractions cannot represent it.
+func AppendDecimalFraction(buf []byte, d dec128.Dec128) ([]byte, error) {
+	if d.IsNaN() {
+		return buf, d.ErrorDetails()
+	}
+
+	buf = appendHead(buf, majorTag, TagDecimalFraction)
+	buf = appendHead(buf, majorArray, 2)
+
+	// exponent is -scale
+	if scale := d.Scale(); scale > 0 {
+		buf = appendHead(buf, majorNegative, uint64(scale)-1)
+	} else {
+		buf = appendHead(buf, majorUnsigned, 0)
+	}
+
+	coef := d.Coefficient()
+	if !d.IsNegative() {
+		if coef.Hi == 0 {
+			return appendHead(buf, majorUnsigned, coef.Lo), nil
+		}
+		return appendBignum(buf, TagPositiveBignum, coef), nil
+	}
+
+	// negative integers are encoded as -1 - n
+	n := uint128.SubUnsafe(coef, uint128.One)
+	if n.Hi == 0 {
+		return appendHead(buf, majorNegative, n.Lo), nil
+	}
+	return appendBignum(buf, TagNegativeBignum, n), nil
+}
+
+// DecodeDecimalFraction decodes a CBOR decimal fraction from the beginning of b.
+// It returns the Dec128 and the number of bytes consumed.
+// Plain integers are not accepted, only tag 4 with an integer or bignum mantissa.
+// In case the value cannot be represented exactly (coefficient overflow or scale above dec128.MaxScale), it returns an error.
+func DecodeDecimalFraction(b []byte) (dec128.Dec128, int, error) {
+	major, tag, pos, err := readHead(b, 0)
+	if err != nil {
+		return dec128.Zero, 0, err
+	}
+	if major != majorTag || tag != TagDecimalFraction {
+		return dec128.Zero, 0, state.InvalidFormat.Error()
+	}
+
+	major, n, pos, err := readHead(b, pos)
+	if err != nil {
+		return dec128.Zero, 0, err
+	}
+	if major != majorArray || n != 2 {
+		return dec128.Zero, 0, state.InvalidFormat.Error()
+	}
+
+	// exponent
+	major, e, pos, err := readHead(b, pos)
+	if err != nil {
+		return dec128.Zero, 0, err
+	}
+	var exp int
+	switch {
+	case major != majorUnsigned && major != majorNegative:
+		return dec128.Zero, 0, state.InvalidFormat.Error()
+	case e > 1_000_000:
+		// far out of range anyway, avoid int overflow
+		e = 1_000_000
+	}
+	if major == majorUnsigned {
+		exp = int(e)
+	} else {
+		exp = -1 - int(e)
+	}
+
+	// mantissa
+	major, m, pos, err := readHead(b, pos)
+	if err != nil {
+		return dec128.Zero, 0, err
+	}
+
+	var coef uint128.Uint128
+	var neg bool
+	switch major {
+	case majorUnsigned:
+		coef = uint128.FromUint64(m)
+	case majorNegative:
+		coef, _ = uint128.FromUint64(m).Add64(1)
+		neg = true
+	case majorTag:
+		if m != TagPositiveBignum && m != TagNegativeBignum {
+			return dec128.Zero, 0, state.InvalidFormat.Error()
+		}
+		neg = m == TagNegativeBignum
+
+		var sz uint64
+		major, sz, pos, err = readHead(b, pos)
+		switch {
+		case err != nil:
+			return dec128.Zero, 0, err
+		case major != majorBytes:
+			return dec128.Zero, 0, state.InvalidFormat.Error()
+		case uint64(len(b)-pos) < sz:
+			return dec128.Zero, 0, io.ErrShortBuffer
+		}
+
+		digits := b[pos : pos+int(sz)]
+		pos += int(sz)
+		for len(digits) > 0 && digits[0] == 0 {
+			digits = digits[1:]
+		}
+		if len(digits) > 16 {
+			return dec128.Zero, 0, state.Overflow.Error()
+		}
+
+		tmp := [16]byte{}
+		copy(tmp[16-len(digits):], digits)
+		coef = uint128.FromBytesBigEndian(tmp)
+
+		if neg {
+			var s state.State
+			coef, s = coef.Add64(1)
+			if s >= state.Error {
+				return dec128.Zero, 0, s.Error()
+			}
+		}
+	default:
+		return dec128.Zero, 0, state.InvalidFormat.Error()
+	}
+
+	d := dec128.FromCoefficient(neg, coef, exp)
+	if d.IsNaN() {
+		return dec128.Zero, 0, d.ErrorDetails()
+	}
+
+	return d, pos, nil
+}
+
+// appendHead appends the shortest CBOR head of the given major type and argument.
+func appendHead(buf []byte, major byte, arg uint64) []byte {
+	major <<= 5
+	switch {
+	case arg < 24:
+		return append(buf, major|byte(arg))
+	case arg <= 0xff:
+		return append(buf, major|24, byte(arg))
+	case arg <= 0xffff:
+		return binary.BigEndian.AppendUint16(append(buf, major|25), uint16(arg))
+	case arg <= 0xffffffff:
+		return binary.BigEndian.AppendUint32(append(buf, major|26), uint32(arg))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, major|27), arg)
+	}
+}
+
+// appendBignum appends u as a bignum with the given tag in the minimal number of bytes.
+func appendBignum(buf []byte, tag uint64, u uint128.Uint128) []byte {
+	tmp := u.BytesBigEndian()
+	i := 0
+	for i < 15 && tmp[i] == 0 {
+		i++
+	}
+	buf = appendHead(buf, majorTag, tag)
+	buf = appendHead(buf, majorBytes, uint64(16-i))
+	return append(buf, tmp[i:]...)
+}
+
+// readHead reads a CBOR head at position pos of b and returns the major type, the argument and the position after the head.
+// Indefinite lengths are not supported.
+func readHead(b []byte, pos int) (byte, uint64, int, error) {
+	if pos >= len(b) {
+		return 0, 0, pos, io.ErrShortBuffer
+	}
+
+	major, info := b[pos]>>5, b[pos]&0x1f
+	pos++
+
+	var sz int
+	switch {
+	case info < 24:
+		return major, uint64(info), pos, nil
+	case info == 24:
+		sz = 1
+	case info == 25:
+		sz = 2
+	case info == 26:
+		sz = 4
+	case info == 27:
+		sz = 8
+	default:
+		return major, 0, pos, state.InvalidFormat.Error()
+	}
+
+	if len(b)-pos < sz {
+		return major, 0, pos, io.ErrShortBuffer
+	}
+
+	var arg uint64
+	for _, c := range b[pos : pos+sz] {
+		arg = arg<<8 | uint64(c)
+	}
+
+	return major, arg, pos + sz, nil
+}
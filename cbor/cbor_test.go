@@ -0,0 +1,112 @@
+package cbor
+
+import (
+	"encoding/hex"
+	"io"
+	"testing"
+
+	"github.com/jokruger/dec128"
+	"github.com/jokruger/dec128/state"
+)
+
+func TestDecimalFraction(t *testing.T) {
+	type testCase struct {
+		i string
+		b string
+	}
+
+	testCases := [...]testCase{
+		{"0", "c4820000"},
+		{"1.5", "c482200f"},
+		{"-1.5", "c482202e"},
+		{"273.15", "c48221196ab3"},
+		{"-0.0000000000000000001", "c4823220"},
+		{"18446744073709551615", "c482001bffffffffffffffff"},
+		{"18446744073709551616", "c48200c249010000000000000000"},
+		{"-18446744073709551616", "c482003bffffffffffffffff"},
+		{"-18446744073709551617", "c48200c349010000000000000000"},
+		{"340282366920938463463374607431768211455", "c48200c250ffffffffffffffffffffffffffffffff"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.i, func(t *testing.T) {
+			d := dec128.FromString(tc.i)
+
+			buf, err := AppendDecimalFraction(nil, d)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s := hex.EncodeToString(buf); s != tc.b {
+				t.Errorf("expected %s, got %s", tc.b, s)
+			}
+
+			r, n, err := DecodeDecimalFraction(append(buf, 0xff))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if n != len(buf) {
+				t.Errorf("expected %d bytes consumed, got %d", len(buf), n)
+			}
+			if !r.Equal(d) || r.Scale() != d.Scale() {
+				t.Errorf("expected %s, got %s", d.StringFixed(), r.StringFixed())
+			}
+		})
+	}
+
+	buf := make([]byte, 0, MaxBytes)
+	d := dec128.FromString("-340282366920938463463374607431768211.455")
+	if n := testing.AllocsPerRun(100, func() { buf, _ = AppendDecimalFraction(buf[:0], d) }); n != 0 {
+		t.Errorf("expected no allocations, got %v", n)
+	}
+	if len(buf) > MaxBytes {
+		t.Errorf("expected at most %d bytes, got %d", MaxBytes, len(buf))
+	}
+
+	if _, err := AppendDecimalFraction(nil, dec128.NaN(state.NaN)); err != state.NaN.Error() {
+		t.Errorf("expected error %v, got %v", state.NaN.Error(), err)
+	}
+}
+
+func TestDecodeDecimalFraction(t *testing.T) {
+	type testCase struct {
+		b string
+		s string
+		e error
+	}
+
+	testCases := [...]testCase{
+		{"c4820205", "500", nil},     // 5E+2
+		{"c48238001805", "0.5", nil}, // non-minimal heads
+		{"c48233c2420100", "0.00000000000000000256", state.ScaleOutOfRange.Error()}, // 256E-20
+		{"c48233c2430a0000", "0.0000000000000065536", nil},                          // 655360E-20
+		{"c48200c2510000000000000000000000000000000001", "1", nil},                  // leading zero bytes
+		{"c48200c2510100000000000000000000000000000000", "", state.Overflow.Error()},
+		{"c48200c350ffffffffffffffffffffffffffffffff", "", state.Overflow.Error()},
+		{"c482182701", "", state.Overflow.Error()}, // 1E+39
+		{"c5820001", "", state.InvalidFormat.Error()},
+		{"c4830001", "", state.InvalidFormat.Error()},
+		{"c48200c401", "", state.InvalidFormat.Error()},
+		{"c48200c201", "", state.InvalidFormat.Error()},
+		{"c4820040", "", state.InvalidFormat.Error()},
+		{"c48200f6", "", state.InvalidFormat.Error()},
+		{"c4820f", "", io.ErrShortBuffer},
+		{"c48200c24201", "", io.ErrShortBuffer},
+		{"c4821901", "", io.ErrShortBuffer},
+		{"c4829f0001ff", "", state.InvalidFormat.Error()}, // indefinite length array
+	}
+
+	for _, tc := range testCases {
+		b, _ := hex.DecodeString(tc.b)
+		d, n, err := DecodeDecimalFraction(b)
+		switch {
+		case tc.e != nil:
+			if err != tc.e {
+				t.Errorf("%s: expected error %v, got %v (%s)", tc.b, tc.e, err, d.String())
+			}
+		case err != nil:
+			t.Errorf("%s: unexpected error %v", tc.b, err)
+		case d.String() != tc.s || n != len(b):
+			t.Errorf("%s: expected %s, got %s (%d bytes)", tc.b, tc.s, d.String(), n)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+// Package cobol converts Dec128 to and from COBOL packed decimal (COMP-3) and zoned decimal (DISPLAY) fields.
+//
+// A packed field of n digits holds two BCD digits per byte and a trailing sign nibble (C positive, D negative, F unsigned) in n/2+1 bytes.
+// A zoned field holds one digit per byte with the sign carried by the zone of the last byte.
+// The decimal point is implied by the scale of the field definition.
+package cobol
+
+import (
+	"github.com/jokruger/dec128"
+	"github.com/jokruger/dec128/state"
+	"github.com/jokruger/dec128/uint128"
+)
+
+// MaxDigits is the maximum number of digits of a field.
+const MaxDigits = 38
+
+// Zone defines the character set of zoned decimal fields.
+type Zone uint8
+
+const (
+	// ZoneEBCDIC encodes digits as 0xF0-0xF9 and the sign in the zone of the last byte (0xC_ positive, 0xD_ negative, 0xF_ unsigned).
+	ZoneEBCDIC Zone = iota
+	// ZoneASCII encodes digits as '0'-'9' and the sign by overpunching the last digit ('{', 'A'-'I' positive, '}', 'J'-'R' negative).
+	ZoneASCII
+)
+
+// Sign nibbles of packed and EBCDIC zoned fields.
+const (
+	SignPositive = byte(0xc)
+	SignNegative = byte(0xd)
+	SignUnsigned = byte(0xf)
+)
+
+// Sign defines whether a field carries a sign.
+type Sign uint8
+
+const (
+	// Signed fields (PIC S9) write sign C for positive values and D for negative values.
+	Signed Sign = iota
+	// Unsigned fields (PIC 9) write sign F, negative values are not allowed.
+	Unsigned
+)
+
+// PackedSize returns the number of bytes of a packed field with the given number of digits, or -1 if digits is out of range.
+func PackedSize(digits int) int {
+	if digits < 1 || digits > MaxDigits {
+		return -1
+	}
+	return digits/2 + 1
+}
+
+// EncodePacked returns d as a packed decimal field of the given number of digits, implied scale and sign.
+// It returns an error if d is NaN, has more digits after the decimal point than scale, does not fit into digits, or is negative for an unsigned field.
+func EncodePacked(d dec128.Dec128, digits int, scale uint8, sign Sign) ([]byte, error) {
+	return AppendPacked(make([]byte, 0, max(PackedSize(digits), 0)), d, digits, scale, sign)
+}
+
+// AppendPacked appends d as a packed decimal field of the given number of digits, implied scale and sign to buf and returns the extended buffer.
+// It returns an error if d is NaN, has more digits after the decimal point than scale, does not fit into digits, or is negative for an unsigned field.
+func AppendPacked(buf []byte, d dec128.Dec128, digits int, scale uint8, sign Sign) ([]byte, error) {
+	if digits < 1 || digits > MaxDigits {
+		return buf, state.InvalidFormat.Error()
+	}
+	nibble, err := signOf(d, sign)
+	if err != nil {
+		return buf, err
+	}
+
+	// an even number of digits leaves the first nibble unused
+	n := PackedSize(digits)
+	tmp := [MaxDigits + 1]byte{}
+	s, err := d.Abs().AppendFixedWidth(tmp[:0], 2*n-1, scale, dec128.SignNone)
+	if err != nil {
+		return buf, err
+	}
+	if digits%2 == 0 && s[0] != '0' {
+		return buf, state.Overflow.Error()
+	}
+
+	for i := 0; i < len(s)-1; i += 2 {
+		buf = append(buf, (s[i]-'0')<<4|(s[i+1]-'0'))
+	}
+
+	return append(buf, (s[len(s)-1]-'0')<<4|nibble), nil
+}
+
+// DecodePacked creates a new Dec128 from a packed decimal field with the given implied scale.
+// Sign nibbles C, A, E and F are positive, D and B are negative.
+// In case of errors (e.g. a digit nibble above 9), it returns NaN with the corresponding error.
+func DecodePacked(b []byte, scale uint8) dec128.Dec128 {
+	switch {
+	case scale > dec128.MaxScale:
+		return dec128.NaN(state.ScaleOutOfRange)
+	case len(b) == 0:
+		return dec128.NaN(state.NotEnoughBytes)
+	}
+
+	var coef uint128.Uint128
+	var st state.State
+	last := len(b) - 1
+	for i, c := range b {
+		hi, lo := uint64(c>>4), uint64(c&0xf)
+		if hi > 9 || (i < last && lo > 9) {
+			return dec128.NaN(state.InvalidFormat)
+		}
+		if coef, st = coef.MulAdd64(10, hi); st >= state.Error {
+			return dec128.NaN(st)
+		}
+		if i < last {
+			if coef, st = coef.MulAdd64(10, lo); st >= state.Error {
+				return dec128.NaN(st)
+			}
+		}
+	}
+
+	neg, ok := signNibble(b[last] & 0xf)
+	if !ok {
+		return dec128.NaN(state.InvalidFormat)
+	}
+
+	return dec128.New(coef, scale, neg)
+}
+
+// EncodeZoned returns d as a zoned decimal field of the given number of digits, implied scale and sign.
+// Unsigned ASCII fields are plain digits without overpunch.
+// It returns an error if d is NaN, has more digits after the decimal point than scale, does not fit into digits, or is negative for an unsigned field.
+func EncodeZoned(d dec128.Dec128, digits int, scale uint8, sign Sign, zone Zone) ([]byte, error) {
+	return AppendZoned(make([]byte, 0, max(digits, 0)), d, digits, scale, sign, zone)
+}
+
+// AppendZoned appends d as a zoned decimal field of the given number of digits, implied scale and sign to buf and returns the extended buffer.
+// Unsigned ASCII fields are plain digits without overpunch.
+// It returns an error if d is NaN, has more digits after the decimal point than scale, does not fit into digits, or is negative for an unsigned field.
+func AppendZoned(buf []byte, d dec128.Dec128, digits int, scale uint8, sign Sign, zone Zone) ([]byte, error) {
+	if digits < 1 || digits > MaxDigits {
+		return buf, state.InvalidFormat.Error()
+	}
+	nibble, err := signOf(d, sign)
+	if err != nil {
+		return buf, err
+	}
+
+	if zone == ZoneASCII {
+		if sign == Unsigned {
+			return d.AppendFixedWidth(buf, digits, scale, dec128.SignNone)
+		}
+		return d.AppendFixedWidth(buf, digits, scale, dec128.SignTrailingOverpunch)
+	}
+
+	start := len(buf)
+	buf, err = d.Abs().AppendFixedWidth(buf, digits, scale, dec128.SignNone)
+	if err != nil {
+		return buf, err
+	}
+
+	for i := start; i < len(buf); i++ {
+		buf[i] = 0xf0 | (buf[i] - '0')
+	}
+
+	buf[len(buf)-1] = nibble<<4 | buf[len(buf)-1]&0xf
+
+	return buf, nil
+}
+
+// DecodeZoned creates a new Dec128 from a zoned decimal field with the given implied scale.
+// EBCDIC fields must use zone F for all but the last byte, the zone of the last byte is the sign (C, A, E and F positive, D and B negative).
+// ASCII fields accept plain digits and an overpunched last digit.
+// In case of errors, it returns NaN with the corresponding error.
+func DecodeZoned(b []byte, scale uint8, zone Zone) dec128.Dec128 {
+	if zone == ZoneASCII {
+		return dec128.FromFixedWidth(b, scale, dec128.SignTrailingOverpunch)
+	}
+
+	switch {
+	case scale > dec128.MaxScale:
+		return dec128.NaN(state.ScaleOutOfRange)
+	case len(b) == 0:
+		return dec128.NaN(state.NotEnoughBytes)
+	}
+
+	var coef uint128.Uint128
+	var st state.State
+	last := len(b) - 1
+	for i, c := range b {
+		z, dg := c>>4, uint64(c&0xf)
+		if dg > 9 || (i < last && z != 0xf) {
+			return dec128.NaN(state.InvalidFormat)
+		}
+		if coef, st = coef.MulAdd64(10, dg); st >= state.Error {
+			return dec128.NaN(st)
+		}
+	}
+
+	neg, ok := signNibble(b[last] >> 4)
+	if !ok {
+		return dec128.NaN(state.InvalidFormat)
+	}
+
+	return dec128.New(coef, scale, neg)
+}
+
+// signOf returns the sign nibble of d in a field with the given sign, or an error if d is NaN or negative for an unsigned field.
+func signOf(d dec128.Dec128, sign Sign) (byte, error) {
+	switch {
+	case d.IsNaN():
+		return 0, d.ErrorDetails()
+	case sign == Unsigned && d.IsNegative():
+		return 0, state.NegativeInUnsignedOp.Error()
+	case sign == Unsigned:
+		return SignUnsigned, nil
+	case d.IsNegative():
+		return SignNegative, nil
+	default:
+		return SignPositive, nil
+	}
+}
+
+// signNibble returns whether the sign nibble is negative and whether it is a valid sign.
+func signNibble(n byte) (bool, bool) {
+	switch n {
+	case 0xb, 0xd:
+		return true, true
+	case 0xa, 0xc, 0xe, 0xf:
+		return false, true
+	default:
+		return false, false
+	}
+}
@@ -0,0 +1,221 @@
+package cobol
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/jokruger/dec128"
+	"github.com/jokruger/dec128/state"
+)
+
+func TestPacked(t *testing.T) {
+	type testCase struct {
+		i      string
+		digits int
+		scale  uint8
+		b      string
+	}
+
+	testCases := [...]testCase{
+		{"0", 1, 0, "0c"},
+		{"123.45", 5, 2, "12345c"},
+		{"-123.45", 5, 2, "12345d"},
+		{"123.45", 6, 2, "0012345c"},
+		{"-1.2", 5, 3, "01200d"},
+		{"99999999999999999999999999999999999999", 38, 0, "099999999999999999999999999999999999999c"},
+		{"-0.0000000000000000001", 19, 19, "0000000000000000001d"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.i, func(t *testing.T) {
+			d := dec128.FromString(tc.i)
+
+			b, err := EncodePacked(d, tc.digits, tc.scale, Signed)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s := hex.EncodeToString(b); s != tc.b {
+				t.Errorf("expected %s, got %s", tc.b, s)
+			}
+			if len(b) != PackedSize(tc.digits) {
+				t.Errorf("expected %d bytes, got %d", PackedSize(tc.digits), len(b))
+			}
+			if r := DecodePacked(b, tc.scale); !r.Equal(d) || r.Scale() != tc.scale {
+				t.Errorf("expected %s, got %s", d.String(), r.StringFixed())
+			}
+		})
+	}
+
+	errs := [...]struct {
+		i      string
+		digits int
+		scale  uint8
+		e      error
+	}{
+		{"123.45", 4, 2, state.Overflow.Error()},
+		{"1000", 4, 1, state.Overflow.Error()},
+		{"1.234", 5, 2, state.RescaleToLowerScale.Error()},
+		{"1", 0, 0, state.InvalidFormat.Error()},
+		{"1", 39, 0, state.InvalidFormat.Error()},
+		{"abc", 5, 0, state.InvalidFormat.Error()},
+	}
+
+	for _, tc := range errs {
+		if _, err := EncodePacked(dec128.FromString(tc.i), tc.digits, tc.scale, Signed); err != tc.e {
+			t.Errorf("%s: expected error %v, got %v", tc.i, tc.e, err)
+		}
+	}
+
+	decode := [...]struct {
+		b string
+		s string
+		e error
+	}{
+		{"12345f", "123.45", nil},
+		{"12345a", "123.45", nil},
+		{"12345b", "-123.45", nil},
+		{"00000d", "0", nil},
+		{"1a345c", "", state.InvalidFormat.Error()},
+		{"12a45c", "", state.InvalidFormat.Error()},
+		{"123459", "", state.InvalidFormat.Error()},
+		{"", "", state.NotEnoughBytes.Error()},
+		{"9999999999999999999999999999999999999999c", "", state.Overflow.Error()},
+	}
+
+	for _, tc := range decode {
+		b, _ := hex.DecodeString(tc.b)
+		d := DecodePacked(b, 2)
+		switch {
+		case tc.e != nil:
+			if d.ErrorDetails() != tc.e {
+				t.Errorf("%s: expected error %v, got %v", tc.b, tc.e, d.ErrorDetails())
+			}
+		case d.String() != tc.s:
+			t.Errorf("%s: expected %s, got %s", tc.b, tc.s, d.String())
+		}
+	}
+
+	if d := DecodePacked([]byte{0x1c}, 20); d.ErrorDetails() != state.ScaleOutOfRange.Error() {
+		t.Errorf("expected error %v, got %v", state.ScaleOutOfRange.Error(), d.ErrorDetails())
+	}
+}
+
+func TestZoned(t *testing.T) {
+	type testCase struct {
+		i      string
+		digits int
+		scale  uint8
+		ebcdic string
+		ascii  string
+	}
+
+	testCases := [...]testCase{
+		{"0", 1, 0, "c0", "{"},
+		{"123.45", 7, 2, "f0f0f1f2f3f4c5", "001234E"},
+		{"-123.45", 7, 2, "f0f0f1f2f3f4d5", "001234N"},
+		{"-123.40", 5, 2, "f1f2f3f4d0", "1234}"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.i, func(t *testing.T) {
+			d := dec128.FromString(tc.i)
+
+			b, err := EncodeZoned(d, tc.digits, tc.scale, Signed, ZoneEBCDIC)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s := hex.EncodeToString(b); s != tc.ebcdic {
+				t.Errorf("expected %s, got %s", tc.ebcdic, s)
+			}
+			if r := DecodeZoned(b, tc.scale, ZoneEBCDIC); !r.Equal(d) || r.Scale() != tc.scale {
+				t.Errorf("expected %s, got %s", d.String(), r.StringFixed())
+			}
+
+			b, err = EncodeZoned(d, tc.digits, tc.scale, Signed, ZoneASCII)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(b) != tc.ascii {
+				t.Errorf("expected %s, got %s", tc.ascii, string(b))
+			}
+			if r := DecodeZoned(b, tc.scale, ZoneASCII); !r.Equal(d) || r.Scale() != tc.scale {
+				t.Errorf("expected %s, got %s", d.String(), r.StringFixed())
+			}
+		})
+	}
+
+	if _, err := EncodeZoned(dec128.FromString("123.45"), 4, 2, Signed, ZoneEBCDIC); err != state.Overflow.Error() {
+		t.Errorf("expected error %v, got %v", state.Overflow.Error(), err)
+	}
+	if _, err := EncodeZoned(dec128.FromString("1"), 0, 2, Signed, ZoneASCII); err != state.InvalidFormat.Error() {
+		t.Errorf("expected error %v, got %v", state.InvalidFormat.Error(), err)
+	}
+
+	decode := [...]struct {
+		b string
+		s string
+		e error
+	}{
+		{"f1f2f3f4f5", "123.45", nil},
+		{"f1f2f3f4b5", "-123.45", nil},
+		{"f1c2f3f4c5", "", state.InvalidFormat.Error()},
+		{"f1f2f3f4fa", "", state.InvalidFormat.Error()},
+		{"f1f2f3f495", "", state.InvalidFormat.Error()},
+		{"", "", state.NotEnoughBytes.Error()},
+	}
+
+	for _, tc := range decode {
+		b, _ := hex.DecodeString(tc.b)
+		d := DecodeZoned(b, 2, ZoneEBCDIC)
+		switch {
+		case tc.e != nil:
+			if d.ErrorDetails() != tc.e {
+				t.Errorf("%s: expected error %v, got %v", tc.b, tc.e, d.ErrorDetails())
+			}
+		case d.String() != tc.s:
+			t.Errorf("%s: expected %s, got %s", tc.b, tc.s, d.String())
+		}
+	}
+
+	if d := DecodeZoned([]byte("12A45"), 2, ZoneASCII); d.ErrorDetails() != state.InvalidFormat.Error() {
+		t.Errorf("expected error %v, got %v", state.InvalidFormat.Error(), d.ErrorDetails())
+	}
+}
+
+func TestUnsigned(t *testing.T) {
+	d := dec128.FromString("123.45")
+
+	b, err := EncodePacked(d, 5, 2, Unsigned)
+	if err != nil || hex.EncodeToString(b) != "12345f" {
+		t.Errorf("expected 12345f, got %x (%v)", b, err)
+	}
+	if r := DecodePacked(b, 2); !r.Equal(d) {
+		t.Errorf("expected %s, got %s", d.String(), r.String())
+	}
+
+	b, err = EncodeZoned(d, 6, 2, Unsigned, ZoneEBCDIC)
+	if err != nil || hex.EncodeToString(b) != "f0f1f2f3f4f5" {
+		t.Errorf("expected f0f1f2f3f4f5, got %x (%v)", b, err)
+	}
+	if r := DecodeZoned(b, 2, ZoneEBCDIC); !r.Equal(d) {
+		t.Errorf("expected %s, got %s", d.String(), r.String())
+	}
+
+	b, err = EncodeZoned(d, 6, 2, Unsigned, ZoneASCII)
+	if err != nil || string(b) != "012345" {
+		t.Errorf("expected 012345, got %s (%v)", b, err)
+	}
+	if r := DecodeZoned(b, 2, ZoneASCII); !r.Equal(d) {
+		t.Errorf("expected %s, got %s", d.String(), r.String())
+	}
+
+	neg := dec128.FromString("-1")
+	if _, err := EncodePacked(neg, 5, 2, Unsigned); err != state.NegativeInUnsignedOp.Error() {
+		t.Errorf("expected error %v, got %v", state.NegativeInUnsignedOp.Error(), err)
+	}
+	for _, zone := range []Zone{ZoneEBCDIC, ZoneASCII} {
+		if _, err := EncodeZoned(neg, 5, 2, Unsigned, zone); err != state.NegativeInUnsignedOp.Error() {
+			t.Errorf("expected error %v, got %v", state.NegativeInUnsignedOp.Error(), err)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+// Package column encodes slices of Dec128 into a compact columnar block, e.g. for time series of prices.
+//
+// When all values are valid, share one scale and have coefficients below 2^60, the block stores the scale once,
+// the first value and the first delta as zig-zag varints, and the delta-of-deltas of the remaining values bit-packed with a common width.
+// Otherwise each value is stored with the varint binary layout of dec128.EncodeBinaryV2.
+//
+// Block layout:
+//
+//	version byte (1)
+//	layout byte (0 shared scale, 1 per value)
+//	count (uvarint)
+//	shared scale: scale byte, first (varint), delta (varint), width byte, packed delta-of-deltas
+//	per value:    count values in the dec128 varint binary layout
+package column
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"math/bits"
+
+	"github.com/jokruger/dec128"
+	"github.com/jokruger/dec128/state"
+	"github.com/jokruger/dec128/uint128"
+)
+
+// Version is the block format version written by Append.
+const Version = 1
+
+// Block layouts
+const (
+	LayoutShared   = 0
+	LayoutPerValue = 1
+)
+
+// coefficients of the shared layout are below 2^60, so delta-of-deltas fit into int64 and their zig-zag form into 63 bits
+const maxSharedCoef = 1 << 60
+
+// Append appends src encoded as a block to buf and returns the extended buffer.
+func Append(buf []byte, src []dec128.Dec128) []byte {
+	scale, ok := sharedScale(src)
+
+	buf = append(buf, Version)
+	if !ok {
+		buf = append(buf, LayoutPerValue)
+		buf = binary.AppendUvarint(buf, uint64(len(src)))
+		for _, d := range src {
+			buf, _ = d.AppendBinaryV2(buf)
+		}
+		return buf
+	}
+
+	buf = append(buf, LayoutShared)
+	buf = binary.AppendUvarint(buf, uint64(len(src)))
+	buf = append(buf, scale)
+
+	n := len(src)
+	if n == 0 {
+		return buf
+	}
+
+	prev := signed(src[0])
+	buf = binary.AppendVarint(buf, prev)
+	if n == 1 {
+		return buf
+	}
+
+	delta := signed(src[1]) - prev
+	buf = binary.AppendVarint(buf, delta)
+	if n == 2 {
+		return buf
+	}
+
+	// first pass finds the bit width of the zig-zag encoded delta-of-deltas
+	var all uint64
+	p, pd := signed(src[1]), delta
+	for _, d := range src[2:] {
+		v := signed(d)
+		all |= zigzag(v - p - pd)
+		p, pd = v, v-p
+	}
+	width := bits.Len64(all)
+	buf = append(buf, byte(width))
+	if width == 0 {
+		return buf
+	}
+
+	// second pass packs them least significant bit first
+	var acc uint64
+	var nbits int
+	p, pd = signed(src[1]), delta
+	for _, d := range src[2:] {
+		v := signed(d)
+		z := zigzag(v - p - pd)
+		p, pd = v, v-p
+
+		acc |= z << nbits
+		if nbits+width >= 64 {
+			buf = binary.LittleEndian.AppendUint64(buf, acc)
+			acc = z >> (64 - nbits)
+			nbits += width - 64
+		} else {
+			nbits += width
+		}
+	}
+	for ; nbits > 0; nbits -= 8 {
+		buf = append(buf, byte(acc))
+		acc >>= 8
+	}
+
+	return buf
+}
+
+// Len returns the number of values in the block b.
+func Len(b []byte) (int, error) {
+	_, n, _, err := readHeader(b)
+	return n, err
+}
+
+// Decode decodes the block b into dst and returns the number of values.
+// It returns io.ErrShortBuffer if dst is shorter than Len(b). It does not allocate.
+func Decode(dst []dec128.Dec128, b []byte) (int, error) {
+	layout, n, pos, err := readHeader(b)
+	switch {
+	case err != nil:
+		return 0, err
+	case len(dst) < n:
+		return 0, io.ErrShortBuffer
+	}
+
+	if layout == LayoutPerValue {
+		for i := range n {
+			k, err := dst[i].DecodeBinary(b[pos:])
+			if err != nil {
+				return 0, err
+			}
+			pos += k
+		}
+		return n, checkEnd(b, pos)
+	}
+
+	if pos >= len(b) {
+		return 0, io.ErrShortBuffer
+	}
+	scale := b[pos]
+	pos++
+	if scale > dec128.MaxScale {
+		return 0, state.ScaleOutOfRange.Error()
+	}
+
+	if n == 0 {
+		return 0, checkEnd(b, pos)
+	}
+
+	prev, k := binary.Varint(b[pos:])
+	if k <= 0 {
+		return 0, state.InvalidFormat.Error()
+	}
+	pos += k
+	if dst[0], err = fromSigned(prev, scale); err != nil {
+		return 0, err
+	}
+	if n == 1 {
+		return n, checkEnd(b, pos)
+	}
+
+	delta, k := binary.Varint(b[pos:])
+	if k <= 0 {
+		return 0, state.InvalidFormat.Error()
+	}
+	pos += k
+	prev += delta
+	if dst[1], err = fromSigned(prev, scale); err != nil {
+		return 0, err
+	}
+	if n == 2 {
+		return n, checkEnd(b, pos)
+	}
+
+	if pos >= len(b) {
+		return 0, io.ErrShortBuffer
+	}
+	width := int(b[pos])
+	pos++
+	if width > 64 {
+		return 0, state.InvalidFormat.Error()
+	}
+
+	size := ((n-2)*width + 7) / 8
+	if len(b)-pos < size {
+		return 0, io.ErrShortBuffer
+	}
+	packed := b[pos : pos+size]
+	pos += size
+
+	mask := ^uint64(0)
+	if width < 64 {
+		mask = 1<<width - 1
+	}
+
+	for i := 2; i < n; i++ {
+		var z uint64
+		if width > 0 {
+			off := (i - 2) * width
+			z = load(packed, off/8) >> (off % 8)
+			if s := off % 8; s > 0 && off/8+8 < len(packed) {
+				z |= uint64(packed[off/8+8]) << (64 - s)
+			}
+			z &= mask
+		}
+		delta += unzigzag(z)
+		prev += delta
+		if dst[i], err = fromSigned(prev, scale); err != nil {
+			return 0, err
+		}
+	}
+
+	return n, checkEnd(b, pos)
+}
+
+// readHeader returns the layout, the number of values and the position after the count.
+func readHeader(b []byte) (int, int, int, error) {
+	if len(b) < 3 {
+		return 0, 0, 0, io.ErrShortBuffer
+	}
+	if b[0] != Version || (b[1] != LayoutShared && b[1] != LayoutPerValue) {
+		return 0, 0, 0, state.InvalidFormat.Error()
+	}
+
+	n, k := binary.Uvarint(b[2:])
+	switch {
+	case k == 0:
+		return 0, 0, 0, io.ErrShortBuffer
+	case k < 0 || n > math.MaxInt32:
+		return 0, 0, 0, state.InvalidFormat.Error()
+	}
+
+	return int(b[1]), int(n), 2 + k, nil
+}
+
+// sharedScale returns the common scale of src and whether src can use the shared layout.
+func sharedScale(src []dec128.Dec128) (uint8, bool) {
+	if len(src) == 0 {
+		return 0, true
+	}
+
+	scale := src[0].Scale()
+	for _, d := range src {
+		if d.IsNaN() || d.Scale() != scale {
+			return 0, false
+		}
+		if c := d.Coefficient(); c.Hi != 0 || c.Lo >= maxSharedCoef {
+			return 0, false
+		}
+	}
+
+	return scale, true
+}
+
+// signed returns the coefficient of d with its sign.
+// called only when the coefficient is below maxSharedCoef
+func signed(d dec128.Dec128) int64 {
+	v := int64(d.Coefficient().Lo)
+	if d.IsNegative() {
+		return -v
+	}
+	return v
+}
+
+func fromSigned(v int64, scale uint8) (dec128.Dec128, error) {
+	if v <= -maxSharedCoef || v >= maxSharedCoef {
+		return dec128.Zero, state.InvalidFormat.Error()
+	}
+	if v < 0 {
+		return dec128.New(uint128.FromUint64(uint64(-v)), scale, true), nil
+	}
+	return dec128.New(uint128.FromUint64(uint64(v)), scale, false), nil
+}
+
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+func unzigzag(z uint64) int64 {
+	return int64(z>>1) ^ -int64(z&1)
+}
+
+// load reads up to 8 bytes starting at i as a little-endian word, missing bytes are zero.
+func load(b []byte, i int) uint64 {
+	if i+8 <= len(b) {
+		return binary.LittleEndian.Uint64(b[i:])
+	}
+	var v uint64
+	for j := len(b) - 1; j >= i; j-- {
+		v = v<<8 | uint64(b[j])
+	}
+	return v
+}
+
+func checkEnd(b []byte, pos int) error {
+	if pos != len(b) {
+		return state.InvalidFormat.Error()
+	}
+	return nil
+}
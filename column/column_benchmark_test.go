@@ -0,0 +1,66 @@
+package column
+
+import (
+	"testing"
+
+	"github.com/jokruger/dec128"
+)
+
+const benchSize = 10000
+
+func BenchmarkColumnAppend(b *testing.B) {
+	src := prices(benchSize, 7)
+	buf := Append(nil, src)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = Append(buf[:0], src)
+	}
+	b.ReportMetric(float64(len(buf))/benchSize, "bytes/value")
+}
+
+func BenchmarkColumnAppendBinary(b *testing.B) {
+	src := prices(benchSize, 7)
+	var buf []byte
+	for _, d := range src {
+		buf, _ = d.AppendBinary(buf)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = buf[:0]
+		for _, d := range src {
+			buf, _ = d.AppendBinary(buf)
+		}
+	}
+	b.ReportMetric(float64(len(buf))/benchSize, "bytes/value")
+}
+
+func BenchmarkColumnDecode(b *testing.B) {
+	src := prices(benchSize, 7)
+	buf := Append(nil, src)
+	dst := make([]dec128.Dec128, len(src))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Decode(dst, buf)
+	}
+}
+
+func BenchmarkColumnDecodeBinary(b *testing.B) {
+	src := prices(benchSize, 7)
+	var buf []byte
+	for _, d := range src {
+		buf, _ = d.AppendBinary(buf)
+	}
+	dst := make([]dec128.Dec128, len(src))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pos := 0
+		for j := range dst {
+			n, _ := dst[j].DecodeBinary(buf[pos:])
+			pos += n
+		}
+	}
+}
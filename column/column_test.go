@@ -0,0 +1,166 @@
+package column
+
+import (
+	"encoding/hex"
+	"io"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/jokruger/dec128"
+	"github.com/jokruger/dec128/state"
+	"github.com/jokruger/dec128/uint128"
+)
+
+// prices returns a random walk of n prices with scale 2.
+func prices(n int, seed uint64) []dec128.Dec128 {
+	r := rand.New(rand.NewPCG(seed, seed))
+	src := make([]dec128.Dec128, n)
+	v := int64(1999)
+	for i := range src {
+		v += r.Int64N(21) - 10
+		if v < 0 {
+			src[i] = dec128.New(uint128.FromUint64(uint64(-v)), 2, true)
+		} else {
+			src[i] = dec128.New(uint128.FromUint64(uint64(v)), 2, false)
+		}
+	}
+	return src
+}
+
+func parse(ss ...string) []dec128.Dec128 {
+	src := make([]dec128.Dec128, len(ss))
+	for i, s := range ss {
+		src[i] = dec128.FromString(s)
+	}
+	return src
+}
+
+func TestColumn(t *testing.T) {
+	type testCase struct {
+		name string
+		src  []dec128.Dec128
+		b    string
+	}
+
+	testCases := [...]testCase{
+		{"empty", nil, "010000" + "00"},
+		{"one", parse("19.99"), "01000102" + "9e1f"},
+		{"two", parse("19.99", "20.01"), "01000202" + "9e1f" + "04"},
+		{"linear", parse("1.00", "1.01", "1.02", "1.03", "1.04"), "01000502" + "c801" + "02" + "00"},
+		{"packed", parse("1.00", "1.01", "1.03", "1.02", "1.02", "1.05"), "01000602" + "c801" + "02" + "03" + "aa0c"},
+		{"negative", parse("-1", "0", "1"), "01000300" + "01" + "02" + "00"},
+		{"mixed scales", parse("1.5", "1.55"), "010102" + "110f" + "129b01"},
+		{"nan", []dec128.Dec128{dec128.FromString("1"), dec128.NaN(state.DivisionByZero)}, "010102" + "1001" + "04"},
+		{"large", parse("1152921504606846976"), "010101" + "10808080808080808010"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := Append(nil, tc.src)
+			if s := hex.EncodeToString(b); s != tc.b {
+				t.Errorf("expected %s, got %s", tc.b, s)
+			}
+			check(t, tc.src, b)
+		})
+	}
+}
+
+func TestColumnRandom(t *testing.T) {
+	for _, n := range []int{3, 10, 100, 1000} {
+		src := prices(n, uint64(n))
+		check(t, src, Append(nil, src))
+	}
+
+	// wide delta-of-deltas use the full 63 bits
+	r := rand.New(rand.NewPCG(1, 2))
+	src := make([]dec128.Dec128, 1000)
+	for i := range src {
+		src[i] = dec128.New(uint128.FromUint64(r.Uint64N(maxSharedCoef)), 0, r.IntN(2) == 0)
+	}
+	b := Append(nil, src)
+	if b[1] != LayoutShared {
+		t.Fatalf("expected shared layout")
+	}
+	check(t, src, b)
+
+	// every width from 1 to 63 bits
+	for w := 1; w < 64; w++ {
+		src := []dec128.Dec128{dec128.Zero, dec128.Zero, dec128.Zero, dec128.Zero, dec128.Zero}
+		v := int64(1)<<(w-1) - 1
+		src[2] = dec128.New(uint128.FromUint64(uint64(v/2)), 0, true)
+		src[3] = dec128.New(uint128.FromUint64(uint64(v/2)), 0, false)
+		check(t, src, Append(nil, src))
+	}
+}
+
+func TestDecodeAllocs(t *testing.T) {
+	src := prices(1000, 42)
+	b := Append(nil, src)
+	dst := make([]dec128.Dec128, len(src))
+	if n := testing.AllocsPerRun(100, func() { Decode(dst, b) }); n != 0 {
+		t.Errorf("expected no allocations, got %v", n)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	type testCase struct {
+		b string
+		e error
+	}
+
+	testCases := [...]testCase{
+		{"", io.ErrShortBuffer},
+		{"0100", io.ErrShortBuffer},
+		{"020000", state.InvalidFormat.Error()},
+		{"010200", state.InvalidFormat.Error()},
+		{"0100ff", io.ErrShortBuffer},
+		{"010001", io.ErrShortBuffer},
+		{"01000114", state.ScaleOutOfRange.Error()},
+		{"0100010280", state.InvalidFormat.Error()},
+		{"010003029e1f04", io.ErrShortBuffer},
+		{"010003029e1f0441", state.InvalidFormat.Error()},
+		{"010003029e1f0403", io.ErrShortBuffer},
+		{"010001029e1f00", state.InvalidFormat.Error()},
+		{"01010110", io.ErrShortBuffer},
+		{"010001" + "0280808080808080808001", state.InvalidFormat.Error()},
+	}
+
+	dst := make([]dec128.Dec128, 10)
+	for _, tc := range testCases {
+		b, _ := hex.DecodeString(tc.b)
+		if _, err := Decode(dst, b); err != tc.e {
+			t.Errorf("%s: expected error %v, got %v", tc.b, tc.e, err)
+		}
+	}
+
+	b := Append(nil, prices(11, 1))
+	if _, err := Decode(dst, b); err != io.ErrShortBuffer {
+		t.Errorf("expected error %v, got %v", io.ErrShortBuffer, err)
+	}
+	if n, err := Len(b); n != 11 || err != nil {
+		t.Errorf("expected 11, got %d (%v)", n, err)
+	}
+}
+
+func check(t *testing.T, src []dec128.Dec128, b []byte) {
+	t.Helper()
+
+	n, err := Len(b)
+	if err != nil || n != len(src) {
+		t.Fatalf("expected %d values, got %d (%v)", len(src), n, err)
+	}
+
+	dst := make([]dec128.Dec128, n)
+	n, err = Decode(dst, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != len(src) {
+		t.Fatalf("expected %d values, got %d", len(src), n)
+	}
+	for i := range src {
+		if dst[i].StringFixed() != src[i].StringFixed() {
+			t.Fatalf("%d: expected %s, got %s", i, src[i].StringFixed(), dst[i].StringFixed())
+		}
+	}
+}
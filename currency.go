@@ -0,0 +1,156 @@
+package dec128
+
+import "github.com/jokruger/dec128/state"
+
+// SymbolPlacement defines where the currency symbol is placed relative to the amount.
+type SymbolPlacement uint8
+
+const (
+	// SymbolBefore places the symbol in front of the amount (e.g. "$1,234.56").
+	SymbolBefore SymbolPlacement = iota
+	// SymbolAfter places the symbol after the amount (e.g. "1.234,56 €").
+	SymbolAfter
+)
+
+// NegativeStyle defines how negative amounts are displayed.
+type NegativeStyle uint8
+
+const (
+	// NegativeMinus prefixes negative amounts with a minus sign (e.g. "-$1,234.56").
+	NegativeMinus NegativeStyle = iota
+	// NegativeParentheses encloses negative amounts in parentheses (e.g. "($1,234.56)").
+	NegativeParentheses
+	// NegativeTrailingMinus appends a minus sign to negative amounts (e.g. "$1,234.56-").
+	NegativeTrailingMinus
+)
+
+// DefaultMinorUnits is the number of minor units used for currencies not known to CurrencyMinorUnits.
+const DefaultMinorUnits = uint8(2)
+
+// minor units of ISO 4217 currencies that differ from DefaultMinorUnits
+var currencyMinorUnits = map[string]uint8{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0,
+	"RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// CurrencyMinorUnits returns the number of minor units (digits after the decimal point) of the ISO 4217 currency code.
+// Codes without an explicit entry use DefaultMinorUnits.
+// The second result is false if code is not a three-letter upper-case code.
+func CurrencyMinorUnits(code string) (uint8, bool) {
+	if u, ok := currencyMinorUnits[code]; ok {
+		return u, true
+	}
+	if len(code) == 3 {
+		for i := range 3 {
+			if code[i] < 'A' || code[i] > 'Z' {
+				return DefaultMinorUnits, false
+			}
+		}
+		return DefaultMinorUnits, true
+	}
+	return DefaultMinorUnits, false
+}
+
+// CurrencyFormat describes how AppendCurrency renders an amount.
+type CurrencyFormat struct {
+	// Code is the ISO 4217 currency code (e.g. "USD"). It defines the number of minor units the amount is rounded to.
+	Code string
+
+	// Symbol is the currency symbol (e.g. "$"). If empty, Code is used instead.
+	Symbol string
+
+	// Placement defines where the symbol is placed relative to the amount.
+	Placement SymbolPlacement
+
+	// Space inserts a space between the symbol and the amount.
+	Space bool
+
+	// Negative defines how negative amounts are displayed.
+	Negative NegativeStyle
+
+	// Zero is the text emitted for amounts that are zero after rounding (e.g. "-"). If empty, zero is formatted as any other amount.
+	Zero string
+
+	// GroupSeparator, if not zero, is inserted between groups of three digits in the integer part.
+	GroupSeparator byte
+
+	// DecimalSeparator replaces the default '.' if not zero.
+	DecimalSeparator byte
+
+	// NaN is the text emitted for NaN values. If empty, NaNStr is used.
+	NaN string
+}
+
+// StringCurrency returns the Dec128 formatted as a currency amount according to f.
+func (d Dec128) StringCurrency(f CurrencyFormat) string {
+	buf := [MaxStrLen * 2]byte{}
+	return string(d.AppendCurrency(buf[:0], f))
+}
+
+// AppendCurrency appends the Dec128 formatted as a currency amount according to f to buf and returns the extended buffer.
+// The amount is rounded half away from zero to the minor units of f.Code.
+// It does not allocate if buf has enough capacity.
+func (d Dec128) AppendCurrency(buf []byte, f CurrencyFormat) []byte {
+	if d.state >= state.Error {
+		if f.NaN != "" {
+			return append(buf, f.NaN...)
+		}
+		return append(buf, NaNStr...)
+	}
+
+	scale, _ := CurrencyMinorUnits(f.Code)
+	d = d.RoundHalfAwayFromZero(scale)
+
+	if f.Zero != "" && d.coef.IsZero() {
+		return append(buf, f.Zero...)
+	}
+
+	neg := d.state == state.Neg && !d.coef.IsZero()
+	if neg {
+		switch f.Negative {
+		case NegativeMinus:
+			buf = append(buf, '-')
+		case NegativeParentheses:
+			buf = append(buf, '(')
+		}
+	}
+
+	sym := f.Symbol
+	if sym == "" {
+		sym = f.Code
+	}
+
+	if f.Placement == SymbolBefore && sym != "" {
+		buf = append(buf, sym...)
+		if f.Space {
+			buf = append(buf, ' ')
+		}
+	}
+
+	buf = d.Abs().AppendFormat(buf, FormatOptions{
+		Scale:            scale,
+		Fixed:            true,
+		GroupSeparator:   f.GroupSeparator,
+		DecimalSeparator: f.DecimalSeparator,
+	})
+
+	if f.Placement == SymbolAfter && sym != "" {
+		if f.Space {
+			buf = append(buf, ' ')
+		}
+		buf = append(buf, sym...)
+	}
+
+	if neg {
+		switch f.Negative {
+		case NegativeParentheses:
+			buf = append(buf, ')')
+		case NegativeTrailingMinus:
+			buf = append(buf, '-')
+		}
+	}
+
+	return buf
+}
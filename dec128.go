@@ -4,6 +4,7 @@ package dec128
 import (
 	"database/sql/driver"
 	"fmt"
+	"math/big"
 
 	"github.com/jokruger/dec128/state"
 	"github.com/jokruger/dec128/uint128"
@@ -188,21 +189,25 @@ func (d Dec128) Compare(other Dec128) int {
 		return d.coef.Compare(other.coef)
 	}
 
+	// rescaling overflows only for the larger magnitude
+	var c int
 	scale := max(d.scale, other.scale)
 	a := d.Rescale(scale)
-	if a.IsNaN() {
-		return 1
-	}
 	b := other.Rescale(scale)
-	if b.IsNaN() {
-		return -1
+	switch {
+	case a.IsNaN():
+		c = 1
+	case b.IsNaN():
+		c = -1
+	default:
+		c = a.coef.Compare(b.coef)
 	}
 
 	if sneg {
-		return -a.coef.Compare(b.coef)
+		return -c
 	}
 
-	return a.coef.Compare(b.coef)
+	return c
 }
 
 // Canonical returns a new Dec128 with the canonical representation.
@@ -260,25 +265,55 @@ func (d Dec128) Copy() Dec128 {
 }
 
 // Scan implements the sql.Scanner interface.
+// It accepts strings and byte slices (without allocating), all integer types, float32 and float64, *big.Int, *big.Rat and nil (as Zero).
+// Floats are converted from their shortest decimal representation. Floats and *big.Rat values with more than MaxScale digits after the decimal point
+// are rounded once, half away from zero, to MaxScale; use FixedDec128 to round them to the scale of the column.
+// In case of errors (including float NaN and infinity), d is set to NaN and the corresponding error is returned.
 func (d *Dec128) Scan(src any) error {
-	var err error
 	switch v := src.(type) {
 	case string:
 		*d = FromString(v)
-		if d.IsNaN() {
-			err = d.ErrorDetails()
-		}
+	case []byte:
+		*d = FromString(v)
 	case int:
 		*d = FromInt64(int64(v))
+	case int8:
+		*d = FromInt64(int64(v))
+	case int16:
+		*d = FromInt64(int64(v))
+	case int32:
+		*d = FromInt64(int64(v))
 	case int64:
 		*d = FromInt64(v)
+	case uint:
+		*d = DecodeFromUint64(uint64(v), 0)
+	case uint8:
+		*d = DecodeFromUint64(uint64(v), 0)
+	case uint16:
+		*d = DecodeFromUint64(uint64(v), 0)
+	case uint32:
+		*d = DecodeFromUint64(uint64(v), 0)
+	case uint64:
+		*d = DecodeFromUint64(v, 0)
+	case float32:
+		*d = scanFloat(float64(v), 32, MaxScale, RoundingHalfAwayFromZero)
+	case float64:
+		*d = scanFloat(v, 64, MaxScale, RoundingHalfAwayFromZero)
+	case *big.Int:
+		*d = fromBigInt(v)
+	case *big.Rat:
+		*d = fromBigRat(v, MaxScale, RoundingHalfAwayFromZero)
 	case nil:
 		*d = Zero
 	default:
-		err = fmt.Errorf("can't scan %T to Dec128: %T is not supported", src, src)
+		return fmt.Errorf("can't scan %T to Dec128: %T is not supported", src, src)
+	}
+
+	if d.IsNaN() {
+		return d.ErrorDetails()
 	}
 
-	return err
+	return nil
 }
 
 // Value implements the driver.Valuer interface.
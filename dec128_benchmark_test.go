@@ -136,3 +136,13 @@ func BenchmarkDec128Add(b *testing.B) {
 		_ = x.Add(y)
 	}
 }
+
+func BenchmarkDec128AppendFormat(b *testing.B) {
+	x := FromString("1234567890.123456789")
+	opts := FormatOptions{Scale: 2, Fixed: true, GroupSeparator: ','}
+	buf := make([]byte, 0, MaxStrLen*2)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = x.AppendFormat(buf[:0], opts)
+	}
+}
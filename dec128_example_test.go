@@ -123,3 +123,23 @@ func ExampleAvg() {
 	// 2
 	// 1.775
 }
+
+func ExampleDec128_AppendFormat() {
+	a := FromString("-1234567.891")
+	buf := make([]byte, 0, 64)
+	buf = a.AppendFormat(buf, FormatOptions{Scale: 2, Fixed: true, GroupSeparator: ','})
+	fmt.Println(string(buf))
+	// Output:
+	// -1,234,567.89
+}
+
+func ExampleDec128_StringCurrency() {
+	f := CurrencyFormat{Code: "USD", Symbol: "$", GroupSeparator: ',', Negative: NegativeParentheses, Zero: "-"}
+	fmt.Println(FromString("1234.567").StringCurrency(f))
+	fmt.Println(FromString("-1234.56").StringCurrency(f))
+	fmt.Println(Zero.StringCurrency(f))
+	// Output:
+	// $1,234.57
+	// ($1,234.56)
+	// -
+}
@@ -4,10 +4,15 @@ import (
 	"bytes"
 	"encoding/gob"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
 	"math"
+	"math/big"
 	"math/rand"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/jokruger/dec128/state"
@@ -798,6 +803,27 @@ func TestCompare4(t *testing.T) {
 	}
 }
 
+func TestCompareRescaleOverflow(t *testing.T) {
+	SetDefaultScale(19)
+
+	// rescaling the value with the larger magnitude to the scale of the other overflows
+	a := FromString("1000000000000000000000000000000")
+	b := FromString("0.0000000000000000001")
+
+	if a.Compare(b) != 1 {
+		t.Errorf("expected 1, got %d", a.Compare(b))
+	}
+	if b.Compare(a) != -1 {
+		t.Errorf("expected -1, got %d", b.Compare(a))
+	}
+	if a.Neg().Compare(b.Neg()) != -1 {
+		t.Errorf("expected -1, got %d", a.Neg().Compare(b.Neg()))
+	}
+	if b.Neg().Compare(a.Neg()) != 1 {
+		t.Errorf("expected 1, got %d", b.Neg().Compare(a.Neg()))
+	}
+}
+
 func TestEqual1(t *testing.T) {
 	SetDefaultScale(19)
 
@@ -3482,3 +3508,1354 @@ func TestInexactFloat64_NaN(t *testing.T) {
 		t.Fatalf("expected error for NaN")
 	}
 }
+
+func TestAppendFormat(t *testing.T) {
+	type testCase struct {
+		i    string
+		opts FormatOptions
+		s    string
+	}
+
+	testCases := [...]testCase{
+		{"0", FormatOptions{}, "0"},
+		{"1.2300", FormatOptions{}, "1.23"},
+		{"-0.001", FormatOptions{}, "-0.001"},
+		{"0.000", FormatOptions{}, "0"},
+		{"123456789012345678901234567890.12", FormatOptions{}, "123456789012345678901234567890.12"},
+		{"1.5", FormatOptions{Scale: 2, Fixed: true}, "1.50"},
+		{"1.005", FormatOptions{Scale: 2, Fixed: true}, "1.01"},
+		{"-1.005", FormatOptions{Scale: 2, Fixed: true}, "-1.01"},
+		{"-0.001", FormatOptions{Scale: 2, Fixed: true}, "0.00"},
+		{"0", FormatOptions{Scale: 3, Fixed: true}, "0.000"},
+		{"12.5", FormatOptions{Scale: 0, Fixed: true}, "13"},
+		{"0.5", FormatOptions{Scale: 25, Fixed: true}, "0.5000000000000000000000000"},
+		{"1234567.891", FormatOptions{GroupSeparator: ','}, "1,234,567.891"},
+		{"-123456", FormatOptions{GroupSeparator: ','}, "-123,456"},
+		{"123", FormatOptions{GroupSeparator: ','}, "123"},
+		{"1234", FormatOptions{GroupSeparator: ' '}, "1 234"},
+		{"1234.5", FormatOptions{GroupSeparator: '.', DecimalSeparator: ',', Scale: 2, Fixed: true}, "1.234,50"},
+		{"1.5", FormatOptions{PlusSign: true}, "+1.5"},
+		{"0", FormatOptions{PlusSign: true}, "+0"},
+		{"-1.5", FormatOptions{PlusSign: true}, "-1.5"},
+		{"12.3", FormatOptions{MinIntDigits: 5}, "00012.3"},
+		{"0.3", FormatOptions{MinIntDigits: 3}, "000.3"},
+		{"1234", FormatOptions{MinIntDigits: 7, GroupSeparator: ','}, "0,001,234"},
+		{"NaN", FormatOptions{}, "NaN"},
+		{"NaN", FormatOptions{NaN: "-", Fixed: true, Scale: 2}, "-"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("TestAppendFormat(%v)", tc), func(t *testing.T) {
+			d := FromString(tc.i)
+			s := string(d.AppendFormat(nil, tc.opts))
+			if s != tc.s {
+				t.Errorf("expected '%s', got: '%s'", tc.s, s)
+			}
+		})
+	}
+
+	buf := make([]byte, 0, 64)
+	buf = append(buf, "x="...)
+	buf = FromString("-1234.5").AppendFormat(buf, FormatOptions{GroupSeparator: ',', Scale: 2, Fixed: true})
+	if string(buf) != "x=-1,234.50" {
+		t.Errorf("expected 'x=-1,234.50', got: '%s'", string(buf))
+	}
+
+	d := FromString("-123456789012345678901.123456789")
+	opts := FormatOptions{Scale: 4, Fixed: true, GroupSeparator: ',', MinIntDigits: 25, PlusSign: true}
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = d.AppendFormat(buf[:0], opts)
+	})
+	if allocs != 0 {
+		t.Errorf("expected 0 allocations, got %v", allocs)
+	}
+}
+
+func TestStringSci(t *testing.T) {
+	type testCase struct {
+		i   string
+		sci string
+		eng string
+	}
+
+	// expected values match Python's decimal module, which implements the General Decimal Arithmetic specification
+	testCases := [...]testCase{
+		{"0", "0", "0"},
+		{"0.00", "0.00", "0.00"},
+		{"0.0000000", "0E-7", "0.0E-6"},
+		{"0.0000000000000000000", "0E-19", "0.0E-18"},
+		{"1", "1", "1"},
+		{"1.00", "1.00", "1.00"},
+		{"1.50", "1.50", "1.50"},
+		{"-1", "-1", "-1"},
+		{"10", "10", "10"},
+		{"1000", "1000", "1000"},
+		{"-12345000", "-12345000", "-12345000"},
+		{"123.45", "123.45", "123.45"},
+		{"0.1", "0.1", "0.1"},
+		{"0.001", "0.001", "0.001"},
+		{"0.000001", "0.000001", "0.000001"},
+		{"0.00000123", "0.00000123", "0.00000123"},
+		{"0.0000001", "1E-7", "100E-9"},
+		{"-0.00000012300", "-1.2300E-7", "-123.00E-9"},
+		{"0.0000000000000000001", "1E-19", "100E-21"},
+		{"0.1234567890123456789", "0.1234567890123456789", "0.1234567890123456789"},
+		{"340282366920938463463374607431768211455", "340282366920938463463374607431768211455", "340282366920938463463374607431768211455"},
+		{"-34028236692093846346.3374607431768211455", "-34028236692093846346.3374607431768211455", "-34028236692093846346.3374607431768211455"},
+		{"NaN", "NaN", "NaN"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("TestStringSci(%v)", tc), func(t *testing.T) {
+			d := FromString(tc.i)
+			if s := d.StringSci(); s != tc.sci {
+				t.Errorf("expected '%s', got: '%s'", tc.sci, s)
+			}
+			if s := d.StringEng(); s != tc.eng {
+				t.Errorf("expected '%s', got: '%s'", tc.eng, s)
+			}
+			if s := string(d.AppendSci([]byte("x"))); s != "x"+tc.sci {
+				t.Errorf("expected 'x%s', got: '%s'", tc.sci, s)
+			}
+			if s := string(d.AppendEng([]byte("x"))); s != "x"+tc.eng {
+				t.Errorf("expected 'x%s', got: '%s'", tc.eng, s)
+			}
+			if r := FromString(tc.sci); !strings.Contains(tc.sci, "E") && (!r.Equal(d) || r.Scale() != d.Scale()) {
+				t.Errorf("expected round trip to %s, got: %s", d.StringFixed(), r.StringFixed())
+			}
+		})
+	}
+}
+
+func TestCurrencyMinorUnits(t *testing.T) {
+	type testCase struct {
+		c  string
+		u  uint8
+		ok bool
+	}
+
+	testCases := [...]testCase{
+		{"USD", 2, true},
+		{"EUR", 2, true},
+		{"JPY", 0, true},
+		{"KWD", 3, true},
+		{"CLF", 4, true},
+		{"", 2, false},
+		{"usd", 2, false},
+		{"US", 2, false},
+	}
+
+	for _, tc := range testCases {
+		u, ok := CurrencyMinorUnits(tc.c)
+		if u != tc.u || ok != tc.ok {
+			t.Errorf("%q: expected %d %v, got %d %v", tc.c, tc.u, tc.ok, u, ok)
+		}
+	}
+}
+
+func TestStringCurrency(t *testing.T) {
+	usd := CurrencyFormat{Code: "USD", Symbol: "$", GroupSeparator: ','}
+	usdAcc := CurrencyFormat{Code: "USD", Symbol: "$", GroupSeparator: ',', Negative: NegativeParentheses, Zero: "-"}
+	eur := CurrencyFormat{Code: "EUR", Space: true, GroupSeparator: '.', DecimalSeparator: ','}
+	eurAfter := CurrencyFormat{Code: "EUR", Symbol: "€", Placement: SymbolAfter, Space: true, GroupSeparator: '.', DecimalSeparator: ',', Negative: NegativeTrailingMinus}
+	jpy := CurrencyFormat{Code: "JPY", Symbol: "¥", GroupSeparator: ','}
+	kwd := CurrencyFormat{Code: "KWD", Space: true}
+
+	type testCase struct {
+		i string
+		f CurrencyFormat
+		s string
+	}
+
+	testCases := [...]testCase{
+		{"1234.56", usd, "$1,234.56"},
+		{"1234.5", usd, "$1,234.50"},
+		{"1234.565", usd, "$1,234.57"},
+		{"-1234.56", usd, "-$1,234.56"},
+		{"0", usd, "$0.00"},
+		{"-1234.56", usdAcc, "($1,234.56)"},
+		{"1234.56", usdAcc, "$1,234.56"},
+		{"0", usdAcc, "-"},
+		{"-0.004", usdAcc, "-"},
+		{"-0.004", usd, "$0.00"},
+		{"1234.56", eur, "EUR 1.234,56"},
+		{"-1234.56", eur, "-EUR 1.234,56"},
+		{"1234.56", eurAfter, "1.234,56 €"},
+		{"-1234.56", eurAfter, "1.234,56 €-"},
+		{"1234.5", jpy, "¥1,235"},
+		{"1.2345", kwd, "KWD 1.235"},
+		{"NaN", usd, "NaN"},
+		{"NaN", CurrencyFormat{Code: "USD", NaN: "n/a"}, "n/a"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("TestStringCurrency(%v)", tc), func(t *testing.T) {
+			d := FromString(tc.i)
+			if s := d.StringCurrency(tc.f); s != tc.s {
+				t.Errorf("expected '%s', got: '%s'", tc.s, s)
+			}
+		})
+	}
+
+	d := FromString("-1234567.891")
+	buf := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = d.AppendCurrency(buf[:0], usdAcc)
+	})
+	if allocs != 0 {
+		t.Errorf("expected 0 allocations, got %v", allocs)
+	}
+}
+
+func TestFixedWidth(t *testing.T) {
+	type testCase struct {
+		i     string
+		w     int
+		scale uint8
+		sign  SignStyle
+		s     string
+	}
+
+	testCases := [...]testCase{
+		{"123.45", 10, 2, SignNone, "0000012345"},
+		{"123.4", 10, 2, SignNone, "0000012340"},
+		{"0", 5, 2, SignNone, "00000"},
+		{"99999", 5, 0, SignNone, "99999"},
+		{"123.45", 10, 2, SignLeading, "+000012345"},
+		{"-123.45", 10, 2, SignLeading, "-000012345"},
+		{"-123.45", 10, 2, SignTrailing, "000012345-"},
+		{"123.45", 10, 2, SignTrailing, "000012345+"},
+		{"123.45", 10, 2, SignTrailingOverpunch, "000001234E"},
+		{"-123.45", 10, 2, SignTrailingOverpunch, "000001234N"},
+		{"-123.40", 10, 2, SignTrailingOverpunch, "000001234}"},
+		{"123.40", 10, 2, SignTrailingOverpunch, "000001234{"},
+		{"-123.45", 10, 2, SignLeadingOverpunch, "}000012345"},
+		{"-923.45", 5, 2, SignLeadingOverpunch, "R2345"},
+		{"12345678901234567890.123456789", 40, 9, SignNone, "0000000000012345678901234567890123456789"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("TestFixedWidth(%v)", tc), func(t *testing.T) {
+			d := FromString(tc.i)
+			buf, err := d.AppendFixedWidth(nil, tc.w, tc.scale, tc.sign)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(buf) != tc.s {
+				t.Errorf("expected '%s', got: '%s'", tc.s, string(buf))
+			}
+			r := FromFixedWidth(buf, tc.scale, tc.sign)
+			if r.IsNaN() {
+				t.Fatalf("unexpected error parsing '%s': %v", string(buf), r.ErrorDetails())
+			}
+			if !r.Equal(d) || r.Scale() != tc.scale {
+				t.Errorf("expected %s, got: %s", d.String(), r.StringFixed())
+			}
+		})
+	}
+
+	errs := [...]testCase{
+		{"123.45", 4, 2, SignNone, ""},
+		{"123.45", 5, 2, SignLeading, ""},
+		{"-1", 5, 2, SignNone, ""},
+		{"1.005", 10, 2, SignNone, ""},
+		{"1", 10, 20, SignNone, ""},
+		{"1", 0, 0, SignNone, ""},
+		{"NaN", 10, 2, SignNone, ""},
+	}
+
+	for _, tc := range errs {
+		if _, err := FromString(tc.i).AppendFixedWidth(nil, tc.w, tc.scale, tc.sign); err == nil {
+			t.Errorf("%v: expected error, got nil", tc)
+		}
+	}
+
+	for _, s := range []string{"", "12a45", "+", "*12345", "12345*", "1234567890123456789012345678901234567890"} {
+		for _, sign := range []SignStyle{SignNone, SignLeading, SignTrailing} {
+			if d := FromFixedWidth(s, 2, sign); !d.IsNaN() {
+				t.Errorf("%q: expected NaN, got: %s", s, d.String())
+			}
+		}
+	}
+
+	if d := FromFixedWidth(" 00012", 2, SignLeading); d.String() != "0.12" {
+		t.Errorf("expected '0.12', got: %s", d.String())
+	}
+	if d := FromFixedWidth("00012", 20, SignNone); !d.IsNaN() {
+		t.Errorf("expected NaN, got: %s", d.String())
+	}
+}
+
+func TestIEEEDecimal128(t *testing.T) {
+	type testCase struct {
+		i   string
+		bid string
+		dpd string
+	}
+
+	testCases := [...]testCase{
+		{"0", "30400000000000000000000000000000", "22080000000000000000000000000000"},
+		{"1", "30400000000000000000000000000001", "22080000000000000000000000000001"},
+		{"-1", "b0400000000000000000000000000001", "a2080000000000000000000000000001"},
+		{"0.1", "303e0000000000000000000000000001", "2207c000000000000000000000000001"},
+		{"1.23", "303c000000000000000000000000007b", "220780000000000000000000000000a3"},
+		{"-7.50", "b03c00000000000000000000000002ee", "a20780000000000000000000000003d0"},
+		{"0.0000000000000000001", "301a0000000000000000000000000001", "22034000000000000000000000000001"},
+		{"1234567890123456789012345678901234", "30403cde6fff9732de825cd07e96aff2", "2608134b9c1e28e56f3c127177823534"},
+		{"9999999999999999999999999999999999", "3041ed09bead87c0378d8e63ffffffff", "6e080ff3fcff3fcff3fcff3fcff3fcff"},
+		{"100000000000000000000000000000000000000", "304a314dc6448d9338c15b0a00000000", "26094000000000000000000000000000"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("TestIEEEDecimal128(%v)", tc.i), func(t *testing.T) {
+			d := FromString(tc.i)
+
+			bid, err := d.ToIEEEDecimal128BID()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s := fmt.Sprintf("%x", bid); s != tc.bid {
+				t.Errorf("expected BID %s, got: %s", tc.bid, s)
+			}
+			if r := FromIEEEDecimal128BID(bid); !r.Equal(d) {
+				t.Errorf("expected %s, got: %s", d.String(), r.String())
+			}
+
+			dpd, err := d.ToIEEEDecimal128DPD()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s := fmt.Sprintf("%x", dpd); s != tc.dpd {
+				t.Errorf("expected DPD %s, got: %s", tc.dpd, s)
+			}
+			if r := FromIEEEDecimal128DPD(dpd); !r.Equal(d) {
+				t.Errorf("expected %s, got: %s", d.String(), r.String())
+			}
+		})
+	}
+}
+
+func TestIEEEDecimal128Special(t *testing.T) {
+	for n := uint64(0); n < 1000; n++ {
+		if r := dpdDecode(dpdEncode(n)); r != n {
+			t.Fatalf("declet round trip failed for %d: got %d", n, r)
+		}
+	}
+
+	// non-canonical declets decode as 8 or 9 in each big digit position
+	if r := dpdDecode(0b1111111111); r != 999 {
+		t.Errorf("expected 999, got %d", r)
+	}
+
+	nan, err := NaN(state.DivisionByZero).ToIEEEDecimal128BID()
+	if err != nil || fmt.Sprintf("%x", nan) != "7c000000000000000000000000000000" {
+		t.Errorf("unexpected NaN encoding %x (%v)", nan, err)
+	}
+	if d := FromIEEEDecimal128BID(nan); d.ErrorDetails() != state.NaN.Error() {
+		t.Errorf("expected NaN, got %s", d.String())
+	}
+	if d := FromIEEEDecimal128DPD(nan); !d.IsNaN() {
+		t.Errorf("expected NaN, got %s", d.String())
+	}
+
+	inf := [16]byte{0xf8}
+	if d := FromIEEEDecimal128BID(inf); d.ErrorDetails() != state.Overflow.Error() {
+		t.Errorf("expected overflow, got %s", d.String())
+	}
+
+	// 35 significant digits cannot be represented exactly
+	if _, err := FromString("12345678901234567890123456789012345").ToIEEEDecimal128BID(); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+	if _, err := FromString("1234567890123456789.0123456789012345").ToIEEEDecimal128DPD(); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+
+	// 1E-20 is not representable, 10E-20 is
+	u := uint128.Uint128{Lo: 1, Hi: uint64(IEEEDecimal128Bias-20) << 49}
+	if d := FromIEEEDecimal128BID(u.BytesBigEndian()); d.ErrorDetails() != state.ScaleOutOfRange.Error() {
+		t.Errorf("expected scale out of range, got %s", d.String())
+	}
+	u.Lo = 10
+	if d := FromIEEEDecimal128BID(u.BytesBigEndian()); d.String() != "0.0000000000000000001" || d.Scale() != 19 {
+		t.Errorf("expected 0.0000000000000000001, got %s", d.String())
+	}
+	u.Lo = 0
+	if d := FromIEEEDecimal128BID(u.BytesBigEndian()); !d.IsZero() || d.Scale() != 19 {
+		t.Errorf("expected zero at scale 19, got %s", d.StringFixed())
+	}
+
+	// 1E+39 overflows
+	u = uint128.Uint128{Lo: 1, Hi: uint64(IEEEDecimal128Bias+39) << 49}
+	if d := FromIEEEDecimal128BID(u.BytesBigEndian()); d.ErrorDetails() != state.Overflow.Error() {
+		t.Errorf("expected overflow, got %s", d.String())
+	}
+
+	// non-canonical BID coefficient is zero
+	u = uint128.Uint128{Lo: 1, Hi: 0x6000000000000000 | uint64(IEEEDecimal128Bias)<<47}
+	if d := FromIEEEDecimal128BID(u.BytesBigEndian()); !d.IsZero() {
+		t.Errorf("expected zero, got %s", d.String())
+	}
+
+	r := rand.New(rand.NewSource(12345))
+	for range 10000 {
+		d := New(uint128.Uint128{Lo: r.Uint64(), Hi: r.Uint64() >> 16}, uint8(r.Intn(int(MaxScale+1))), r.Intn(2) == 1)
+		bid, err := d.ToIEEEDecimal128BID()
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", d.String(), err)
+		}
+		if x := FromIEEEDecimal128BID(bid); !x.Equal(d) {
+			t.Fatalf("BID round trip failed: %s != %s", x.String(), d.String())
+		}
+		dpd, err := d.ToIEEEDecimal128DPD()
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", d.String(), err)
+		}
+		if x := FromIEEEDecimal128DPD(dpd); !x.Equal(d) {
+			t.Fatalf("DPD round trip failed: %s != %s", x.String(), d.String())
+		}
+	}
+}
+
+func TestAvroDecimal(t *testing.T) {
+	type testCase struct {
+		i     string
+		scale uint8
+		b     string
+	}
+
+	testCases := [...]testCase{
+		{"0", 2, "00"},
+		{"0.01", 2, "01"},
+		{"-0.01", 2, "ff"},
+		{"1.27", 2, "7f"},
+		{"1.28", 2, "0080"},
+		{"-1.28", 2, "80"},
+		{"-1.29", 2, "ff7f"},
+		{"123.45", 2, "3039"},
+		{"-123.45", 2, "cfc7"},
+		{"-123.4", 2, "cfcc"},
+		{"170141183460469231731687303715884105728", 0, "0080000000000000000000000000000000"},
+		{"-170141183460469231731687303715884105728", 0, "80000000000000000000000000000000"},
+		{"340282366920938463463374607431768211455", 0, "00ffffffffffffffffffffffffffffffff"},
+		{"-340282366920938463463374607431768211455", 0, "ff00000000000000000000000000000001"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("TestAvroDecimal(%v)", tc.i), func(t *testing.T) {
+			d := FromString(tc.i)
+			buf, err := d.AppendAvroDecimal(nil, tc.scale)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s := fmt.Sprintf("%x", buf); s != tc.b {
+				t.Errorf("expected %s, got: %s", tc.b, s)
+			}
+			if r := DecodeAvroDecimal(buf, tc.scale); !r.Equal(d) || r.Scale() != tc.scale {
+				t.Errorf("expected %s, got: %s", d.String(), r.StringFixed())
+			}
+
+			buf, err = d.AppendAvroDecimalFixed(nil, 20, tc.scale)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(buf) != 20 {
+				t.Errorf("expected 20 bytes, got: %d", len(buf))
+			}
+			if r := DecodeAvroDecimal(buf, tc.scale); !r.Equal(d) {
+				t.Errorf("expected %s, got: %s", d.String(), r.String())
+			}
+		})
+	}
+
+	if _, err := FromString("1.234").AppendAvroDecimal(nil, 2); err != state.RescaleToLowerScale.Error() {
+		t.Errorf("expected rescale error, got: %v", err)
+	}
+	if _, err := NaN(state.NaN).AppendAvroDecimal(nil, 2); err != state.NaN.Error() {
+		t.Errorf("expected NaN error, got: %v", err)
+	}
+	if _, err := FromString("1.28").AppendAvroDecimalFixed(nil, 1, 2); err != state.Overflow.Error() {
+		t.Errorf("expected overflow error, got: %v", err)
+	}
+	if d := DecodeAvroDecimal(nil, 2); d.ErrorDetails() != state.NotEnoughBytes.Error() {
+		t.Errorf("expected not enough bytes error, got: %v", d.ErrorDetails())
+	}
+	if d := DecodeAvroDecimal([]byte{1}, 20); d.ErrorDetails() != state.ScaleOutOfRange.Error() {
+		t.Errorf("expected scale out of range error, got: %v", d.ErrorDetails())
+	}
+	if d := DecodeAvroDecimal([]byte{0xf6}, 20); d.String() != "-0.0000000000000000001" || d.Scale() != 19 {
+		t.Errorf("expected -0.0000000000000000001 with scale 19, got: %s (%d)", d.String(), d.Scale())
+	}
+	if d := DecodeAvroDecimal([]byte{0}, 200); !d.IsZero() || d.Scale() != 19 {
+		t.Errorf("expected 0 with scale 19, got: %s (%d)", d.String(), d.Scale())
+	}
+	if d := DecodeAvroDecimal([]byte{0x01, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, 0); d.ErrorDetails() != state.Overflow.Error() {
+		t.Errorf("expected overflow error, got: %v", d.ErrorDetails())
+	}
+	if d := DecodeAvroDecimal([]byte{0xff, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, 0); d.ErrorDetails() != state.Overflow.Error() {
+		t.Errorf("expected overflow error, got: %v", d.ErrorDetails())
+	}
+}
+
+func TestSortableKey(t *testing.T) {
+	type testCase struct {
+		i string
+		k string
+	}
+
+	testCases := [...]testCase{
+		{"0", "02"},
+		{"0.000", "02"},
+		{"1", "03410b00"},
+		{"1.00", "03410b00"},
+		{"100", "03430b00"},
+		{"-1", "01bef4ff"},
+		{"12.345", "03420d233300"},
+		{"0.0000000000000000001", "032e0b00"},
+		{"340282366920938463463374607431768211455", "03672303532546155e55402f224b3d4b204d530c2e3300"},
+		{"-340282366920938463463374607431768211455", "0198dcfcacdab9eaa1aabfd0ddb4c2b4dfb2acf3d1ccff"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("TestSortableKey(%v)", tc.i), func(t *testing.T) {
+			d := FromString(tc.i)
+			k := d.AppendSortableKey(nil)
+			if s := fmt.Sprintf("%x", k); s != tc.k {
+				t.Errorf("expected %s, got: %s", tc.k, s)
+			}
+			r, n, err := DecodeSortableKey(append(k, 0xaa))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if n != len(k) {
+				t.Errorf("expected %d bytes consumed, got: %d", len(k), n)
+			}
+			if !r.Equal(d) || r.Scale() != d.Canonical().Scale() {
+				t.Errorf("expected %s, got: %s", d.String(), r.StringFixed())
+			}
+
+			k = d.AppendSortableKeyWithScale(nil)
+			r, n, err = DecodeSortableKeyWithScale(k)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if n != len(k) || !r.Equal(d) || r.Scale() != d.Scale() {
+				t.Errorf("expected %s, got: %s", d.StringFixed(), r.StringFixed())
+			}
+		})
+	}
+
+	r := rand.New(rand.NewSource(12345))
+	rnd := func() Dec128 {
+		if r.Intn(50) == 0 {
+			return NaN(state.DivisionByZero)
+		}
+		coef := uint128.Uint128{Lo: r.Uint64() >> r.Intn(64)}
+		if r.Intn(4) == 0 {
+			coef.Hi = r.Uint64() >> r.Intn(64)
+		}
+		if r.Intn(10) == 0 && coef.Hi == 0 {
+			coef, _ = coef.Mul64(Pow10Uint64[r.Intn(5)])
+		}
+		return New(coef, uint8(r.Intn(int(MaxScale+1))), r.Intn(2) == 0)
+	}
+
+	for range 10000 {
+		a, b := rnd(), rnd()
+		ka, kb := a.AppendSortableKey(nil), b.AppendSortableKey(nil)
+		if c := bytes.Compare(ka, kb); c != a.Compare(b) {
+			t.Fatalf("%s vs %s: key order %d, Compare %d", a.StringFixed(), b.StringFixed(), c, a.Compare(b))
+		}
+		if len(ka) > SortableKeyMaxSize {
+			t.Fatalf("%s: key of %d bytes", a.String(), len(ka))
+		}
+
+		ka, kb = a.AppendSortableKeyWithScale(nil), b.AppendSortableKeyWithScale(nil)
+		c := bytes.Compare(ka, kb)
+		switch cmp := a.Compare(b); {
+		case cmp != 0 && c != cmp:
+			t.Fatalf("%s vs %s: key order %d, Compare %d", a.StringFixed(), b.StringFixed(), c, cmp)
+		case cmp == 0 && !a.IsNaN() && c != cmpScale(a, b):
+			t.Fatalf("%s vs %s: key order %d", a.StringFixed(), b.StringFixed(), c)
+		}
+
+		x, _, err := DecodeSortableKeyWithScale(ka)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", a.StringFixed(), err)
+		}
+		if x.StringFixed() != a.StringFixed() {
+			t.Fatalf("expected %s, got: %s", a.StringFixed(), x.StringFixed())
+		}
+	}
+
+	invalid := [...]string{"", "04", "00", "0001", "03", "0341", "03410b", "034165", "034100", "0341650b00", "03010b00"}
+	for _, s := range invalid {
+		var b []byte
+		fmt.Sscanf(s, "%x", &b)
+		if d, _, err := DecodeSortableKey(b); err == nil {
+			t.Errorf("%q: expected error, got: %s", s, d.String())
+		}
+	}
+}
+
+func cmpScale(a, b Dec128) int {
+	switch {
+	case a.Scale() < b.Scale():
+		return -1
+	case a.Scale() > b.Scale():
+		return 1
+	default:
+		return 0
+	}
+}
+
+func TestBinaryV2(t *testing.T) {
+	type testCase struct {
+		i string
+		b string
+	}
+
+	testCases := [...]testCase{
+		{"0", "1000"},
+		{"0.00", "1200"},
+		{"19.99", "12cf0f"},
+		{"-1", "1801"},
+		{"-0.0000000000000000001", "5b01"},
+		{"1.2345678901234567890", "53d295fcd8ceb1aaaaab01"},
+		{"340282366920938463463374607431768211455", "10ffffffffffffffffffffffffffffffffffff03"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("TestBinaryV2(%v)", tc.i), func(t *testing.T) {
+			d := FromString(tc.i)
+
+			b, err := d.AppendBinaryV2(nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s := fmt.Sprintf("%x", b); s != tc.b {
+				t.Errorf("expected %s, got: %s", tc.b, s)
+			}
+			if len(b) != d.BinarySizeV2() {
+				t.Errorf("expected size %d, got: %d", len(b), d.BinarySizeV2())
+			}
+
+			var r Dec128
+			if err := r.UnmarshalBinary(b); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if r.StringFixed() != d.StringFixed() {
+				t.Errorf("expected %s, got: %s", d.StringFixed(), r.StringFixed())
+			}
+
+			var w bytes.Buffer
+			if err := d.WriteBinaryV2(&w); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			w.WriteByte(0xaa)
+			r = Zero
+			if err := r.ReadBinary(&w); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if r.StringFixed() != d.StringFixed() || w.Len() != 1 {
+				t.Errorf("expected %s, got: %s", d.StringFixed(), r.StringFixed())
+			}
+		})
+	}
+
+	// both layouts decode from the same stream
+	var w bytes.Buffer
+	values := []Dec128{FromString("19.99"), FromString("-123.456"), NaN(state.DivisionByZero), Zero}
+	for _, v := range values {
+		v.WriteBinary(&w)
+		v.WriteBinaryV2(&w)
+	}
+	for _, v := range values {
+		for range 2 {
+			var r Dec128
+			if err := r.ReadBinary(&w); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !r.Equal(v) {
+				t.Errorf("expected %s, got: %s", v.String(), r.String())
+			}
+		}
+	}
+
+	invalid := [...]string{"14", "1480", "7401", "10ffffffffffffffffffffffffffffffffffff04", "10ffffffffffffffffffffffffffffffffffffff01"}
+	for _, s := range invalid {
+		var b []byte
+		fmt.Sscanf(s, "%x", &b)
+		var r Dec128
+		if err := r.UnmarshalBinary(b); err == nil {
+			t.Errorf("%s: expected error, got: %s", s, r.String())
+		}
+		if err := r.ReadBinary(bytes.NewReader(b)); err == nil {
+			t.Errorf("%s: expected error, got: %s", s, r.String())
+		}
+	}
+
+	if _, err := FromString("19.99").EncodeBinaryV2(make([]byte, 2)); err != io.ErrShortBuffer {
+		t.Errorf("expected short buffer error, got: %v", err)
+	}
+}
+
+func TestJSONNumber(t *testing.T) {
+	type doc struct {
+		S Dec128
+		N JSONNumber
+		P *JSONNumber `json:",omitempty"`
+	}
+
+	type testCase struct {
+		i string
+		j string
+	}
+
+	testCases := [...]testCase{
+		{"0", `{"S":"0","N":0}`},
+		{"1.50", `{"S":"1.5","N":1.5}`},
+		{"-123.456", `{"S":"-123.456","N":-123.456}`},
+		{"12345678901234567890.1234567890123456789", `{"S":"12345678901234567890.1234567890123456789","N":12345678901234567890.1234567890123456789}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("TestJSONNumber(%v)", tc.i), func(t *testing.T) {
+			d := FromString(tc.i)
+			b, err := json.Marshal(doc{S: d, N: JSONNumber(d)})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(b) != tc.j {
+				t.Errorf("expected %s, got: %s", tc.j, string(b))
+			}
+
+			var r doc
+			if err := json.Unmarshal(b, &r); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !r.S.Equal(d) || !Dec128(r.N).Equal(d) {
+				t.Errorf("expected %s, got: %s and %s", d.String(), r.S.String(), r.N.String())
+			}
+		})
+	}
+
+	// both forms are accepted by both types
+	var r doc
+	if err := json.Unmarshal([]byte(`{"S":1.25,"N":"2.5","P":3}`), &r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.S.String() != "1.25" || r.N.String() != "2.5" || r.P.String() != "3" {
+		t.Errorf("unexpected values: %s %s %s", r.S.String(), r.N.String(), r.P.String())
+	}
+
+	// "NaN" is only accepted with an explicit NaN mode
+	for _, j := range []string{`{"S":"NaN"}`, `{"N":"NaN"}`} {
+		if err := json.Unmarshal([]byte(j), &r); err == nil {
+			t.Errorf("%s: expected error", j)
+		}
+	}
+
+	if err := json.Unmarshal([]byte(`{"N":"1x"}`), &r); err == nil {
+		t.Errorf("expected error")
+	}
+
+	if b, err := json.Marshal(doc{S: NaN(state.DivisionByZero), N: JSONNumber(NaN(state.DivisionByZero))}); err != nil || string(b) != `{"S":"NaN","N":"NaN"}` {
+		t.Errorf("unexpected result: %s (%v)", string(b), err)
+	}
+}
+
+func TestJSONNaN(t *testing.T) {
+	type doc struct {
+		SS JSONString[JSONNaNString]
+		NS JSONNumberNaN[JSONNaNString]
+		SN JSONString[JSONNaNNull]
+		NN JSONNumberNaN[JSONNaNNull]
+		SE JSONString[JSONNaNError]
+		NE JSONNumberNaN[JSONNaNError]
+	}
+
+	d := FromString("1.5")
+	v := doc{JSONString[JSONNaNString](d), JSONNumberNaN[JSONNaNString](d), JSONString[JSONNaNNull](d), JSONNumberNaN[JSONNaNNull](d), JSONString[JSONNaNError](d), JSONNumberNaN[JSONNaNError](d)}
+	b, err := json.Marshal(v)
+	if s := `{"SS":"1.5","NS":1.5,"SN":"1.5","NN":1.5,"SE":"1.5","NE":1.5}`; err != nil || string(b) != s {
+		t.Errorf("expected %s, got: %s (%v)", s, string(b), err)
+	}
+
+	nan := NaN(state.DivisionByZero)
+	v = doc{SS: JSONString[JSONNaNString](nan), NS: JSONNumberNaN[JSONNaNString](nan), SN: JSONString[JSONNaNNull](nan), NN: JSONNumberNaN[JSONNaNNull](nan)}
+	b, err = json.Marshal(v)
+	if s := `{"SS":"NaN","NS":"NaN","SN":null,"NN":null,"SE":"0","NE":0}`; err != nil || string(b) != s {
+		t.Fatalf("expected %s, got: %s (%v)", s, string(b), err)
+	}
+
+	// NaN is read back as NaN, not as 0
+	var r doc
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !Dec128(r.SS).IsNaN() || !Dec128(r.NS).IsNaN() || !Dec128(r.SN).IsNaN() || !Dec128(r.NN).IsNaN() || !Dec128(r.SE).IsZero() {
+		t.Errorf("unexpected values: %s %s %s %s %s", r.SS.String(), r.NS.String(), r.SN.String(), r.NN.String(), r.SE.String())
+	}
+
+	for _, v := range []doc{{SE: JSONString[JSONNaNError](nan)}, {NE: JSONNumberNaN[JSONNaNError](nan)}} {
+		if b, err := json.Marshal(v); err == nil || !strings.Contains(err.Error(), state.DivisionByZero.Error().Error()) {
+			t.Errorf("expected error, got: %s (%v)", string(b), err)
+		}
+	}
+
+	for _, j := range []string{`{"SN":"NaN"}`, `{"NN":"NaN"}`, `{"SE":"NaN"}`, `{"NE":"NaN"}`} {
+		if err := json.Unmarshal([]byte(j), &r); err == nil {
+			t.Errorf("%s: expected error", j)
+		}
+	}
+
+	var n NullDec128
+	if err := json.Unmarshal(nullValue, &n); err != nil || n.Valid {
+		t.Errorf("expected invalid NullDec128, got: %v (%v)", n.Valid, err)
+	}
+}
+
+type testStrictAmount struct{}
+
+func (testStrictAmount) MaxScale() uint8 { return 2 }
+func (testStrictAmount) MaxAbs() Dec128  { return FromInt64(1000000) }
+
+func TestStrict(t *testing.T) {
+	type doc struct {
+		A StrictLimited[testStrictAmount]
+	}
+
+	type testCase struct {
+		j string
+		s string
+		e error
+	}
+
+	testCases := [...]testCase{
+		{`{"A":"1.50"}`, "1.5", nil},
+		{`{"A":"-0.01"}`, "-0.01", nil},
+		{`{"A":"1000000"}`, "1000000", nil},
+		{`{"A":"-1000000.00"}`, "-1000000", nil},
+		{`{"A":"1.230"}`, "1.23", nil},
+		{`{"A":null}`, "", state.InvalidFormat.Error()},
+		{`{"A":""}`, "", state.InvalidFormat.Error()},
+		{`{"A":1.5}`, "", state.InvalidFormat.Error()},
+		{`{"A":"NaN"}`, "", state.NaN.Error()},
+		{`{"A":"1x"}`, "", state.InvalidFormat.Error()},
+		{`{"A":"1.234"}`, "", state.ScaleOutOfRange.Error()},
+		{`{"A":"1000000.01"}`, "", state.Overflow.Error()},
+		{`{"A":"-1000001"}`, "", state.Overflow.Error()},
+	}
+
+	for _, tc := range testCases {
+		var r doc
+		err := json.Unmarshal([]byte(tc.j), &r)
+		switch {
+		case tc.e != nil:
+			if !errors.Is(err, tc.e) {
+				t.Errorf("%s: expected error %v, got: %v (%s)", tc.j, tc.e, err, r.A.String())
+			}
+		case err != nil:
+			t.Errorf("%s: unexpected error: %v", tc.j, err)
+		case r.A.String() != tc.s:
+			t.Errorf("%s: expected %s, got: %s", tc.j, tc.s, r.A.String())
+		}
+	}
+
+	b, err := json.Marshal(doc{A: StrictLimited[testStrictAmount](FromString("-12.5"))})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(b) != `{"A":"-12.5"}` {
+		t.Errorf("expected %s, got: %s", `{"A":"-12.5"}`, string(b))
+	}
+
+	type plain struct {
+		A Strict
+		P *Strict
+	}
+
+	var r plain
+	if err := json.Unmarshal([]byte(`{"A":"12345678901234567890.1234567890123456789"}`), &r); err != nil || r.A.String() != "12345678901234567890.1234567890123456789" {
+		t.Errorf("unexpected result: %s (%v)", r.A.String(), err)
+	}
+	for _, j := range []string{`{"A":null}`, `{"A":""}`, `{"A":1}`, `{"A":"NaN"}`} {
+		if err := json.Unmarshal([]byte(j), &r); err == nil {
+			t.Errorf("%s: expected error", j)
+		}
+	}
+
+	// a missing key is detected with a pointer field
+	if err := json.Unmarshal([]byte(`{"A":"1"}`), &r); err != nil || r.P != nil {
+		t.Errorf("expected nil pointer, got: %v (%v)", r.P, err)
+	}
+	if err := json.Unmarshal([]byte(`{"P":"2.5"}`), &r); err != nil || r.P == nil || r.P.String() != "2.5" {
+		t.Errorf("expected 2.5, got: %v (%v)", r.P, err)
+	}
+}
+
+func TestNullDec128(t *testing.T) {
+	var n NullDec128
+
+	if err := n.Scan("1.50"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !n.Valid || n.String() != "1.5" {
+		t.Errorf("expected valid 1.5, got: %v %s", n.Valid, n.String())
+	}
+	if v, err := n.Value(); err != nil || v != "1.5" {
+		t.Errorf("expected 1.5, got: %v (%v)", v, err)
+	}
+
+	if err := n.Scan(nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if n.Valid || !n.Dec128.IsZero() {
+		t.Errorf("expected invalid, got: %v %s", n.Valid, n.Dec128.String())
+	}
+	if v, err := n.Value(); err != nil || v != nil {
+		t.Errorf("expected nil, got: %v (%v)", v, err)
+	}
+
+	if err := n.Scan("1x"); err == nil || n.Valid {
+		t.Errorf("expected error and invalid value, got: %v %v", err, n.Valid)
+	}
+
+	type doc struct {
+		A NullDec128
+		B NullDec128
+	}
+
+	b, err := json.Marshal(doc{A: NewNullDec128(FromString("-2.25"))})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(b) != `{"A":"-2.25","B":null}` {
+		t.Errorf("expected %s, got: %s", `{"A":"-2.25","B":null}`, string(b))
+	}
+
+	r := doc{B: NewNullDec128(Decimal1)}
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !r.A.Valid || r.A.String() != "-2.25" || r.B.Valid {
+		t.Errorf("unexpected values: %v %s, %v %s", r.A.Valid, r.A.String(), r.B.Valid, r.B.String())
+	}
+
+	if err := json.Unmarshal([]byte(`{"A":"0","B":1}`), &r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !r.A.Valid || !r.A.IsZero() || !r.B.Valid || r.B.String() != "1" {
+		t.Errorf("unexpected values: %v %s, %v %s", r.A.Valid, r.A.String(), r.B.Valid, r.B.String())
+	}
+
+	if s, err := (NullDec128{}).MarshalText(); err != nil || len(s) != 0 {
+		t.Errorf("expected empty text, got: %q (%v)", s, err)
+	}
+	if err := n.UnmarshalText([]byte("3.5")); err != nil || !n.Valid || n.String() != "3.5" {
+		t.Errorf("expected valid 3.5, got: %v %s (%v)", n.Valid, n.String(), err)
+	}
+	if err := n.UnmarshalText(nil); err != nil || n.Valid {
+		t.Errorf("expected invalid, got: %v %s (%v)", n.Valid, n.String(), err)
+	}
+}
+
+func TestNullDec128Binary(t *testing.T) {
+	values := [...]NullDec128{{}, NewNullDec128(Zero), NewNullDec128(FromString("-12.50")), NewNullDec128(NaN(state.Overflow))}
+
+	for _, v := range values {
+		b, err := v.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", v.String(), err)
+		}
+		r := NewNullDec128(Decimal1)
+		if err := r.UnmarshalBinary(b); err != nil {
+			t.Fatalf("%s: unexpected error: %v", v.String(), err)
+		}
+		if r.Valid != v.Valid || !r.Dec128.Equal(v.Dec128) || r.Scale() != v.Scale() {
+			t.Errorf("expected %v %s, got: %v %s", v.Valid, v.String(), r.Valid, r.String())
+		}
+	}
+
+	type doc struct {
+		A NullDec128
+		B NullDec128
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(doc{A: NewNullDec128(FromString("1.5")), B: NewNullDec128(Zero)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var r doc
+	if err := gob.NewDecoder(&buf).Decode(&r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !r.A.Valid || r.A.String() != "1.5" || !r.B.Valid || !r.B.IsZero() {
+		t.Errorf("unexpected values: %v %s, %v %s", r.A.Valid, r.A.String(), r.B.Valid, r.B.String())
+	}
+
+	for _, b := range [][]byte{nil, {0, 0}, {2}, {1}} {
+		var n NullDec128
+		if err := n.UnmarshalBinary(b); err == nil {
+			t.Errorf("%x: expected error, got: %v %s", b, n.Valid, n.String())
+		}
+	}
+}
+
+func TestScanSources(t *testing.T) {
+	type testCase struct {
+		src any
+		s   string
+		e   error
+	}
+
+	r, _ := new(big.Rat).SetString("-1/3")
+	b, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+	h := new(big.Int).Lsh(big.NewInt(1), 128)
+
+	testCases := [...]testCase{
+		{[]byte("-12.340"), "-12.34", nil},
+		{[]byte("1x"), "", state.InvalidFormat.Error()},
+		{int8(-8), "-8", nil},
+		{int16(-16), "-16", nil},
+		{int32(-32), "-32", nil},
+		{uint(1), "1", nil},
+		{uint8(8), "8", nil},
+		{uint16(16), "16", nil},
+		{uint32(32), "32", nil},
+		{uint64(math.MaxUint64), "18446744073709551615", nil},
+		{float32(0.1), "0.1", nil},
+		{float64(0.1), "0.1", nil},
+		{float64(-2.675), "-2.675", nil},
+		{float64(1.496e-18), "0.0000000000000000015", nil},
+		{float64(1e-30), "0", nil},
+		{float64(1e30), "1000000000000000000000000000000", nil},
+		{float64(1e40), "", state.Overflow.Error()},
+		{math.NaN(), "", state.NaN.Error()},
+		{math.Inf(-1), "", state.NaN.Error()},
+		{b, "-123456789012345678901234567890", nil},
+		{h, "", state.Overflow.Error()},
+		{(*big.Int)(nil), "0", nil},
+		{big.NewRat(1, 8), "0.125", nil},
+		{big.NewRat(-5, 2), "-2.5", nil},
+		{r, "-0.3333333333333333333", nil},
+		{(*big.Rat)(nil), "0", nil},
+	}
+
+	for _, tc := range testCases {
+		var d Dec128
+		err := d.Scan(tc.src)
+		switch {
+		case tc.e != nil:
+			if err != tc.e || !d.IsNaN() {
+				t.Errorf("%T(%v): expected error %v, got: %v (%s)", tc.src, tc.src, tc.e, err, d.String())
+			}
+		case err != nil:
+			t.Errorf("%T(%v): unexpected error: %v", tc.src, tc.src, err)
+		case d.String() != tc.s:
+			t.Errorf("%T(%v): expected %s, got: %s", tc.src, tc.src, tc.s, d.String())
+		}
+	}
+
+	if h.BitLen() != 129 || b.Sign() >= 0 {
+		t.Errorf("source values must not be modified")
+	}
+
+	var d Dec128
+	var src any = []byte("123.45")
+	if n := testing.AllocsPerRun(100, func() { _ = d.Scan(src) }); n != 0 {
+		t.Errorf("expected no allocations, got: %v", n)
+	}
+}
+
+func TestRound(t *testing.T) {
+	type testCase struct {
+		i string
+		m RoundingMode
+		s string
+	}
+
+	testCases := [...]testCase{
+		{"-1.235", RoundingHalfAwayFromZero, "-1.24"},
+		{"-1.235", RoundingHalfTowardZero, "-1.23"},
+		{"1.245", RoundingBank, "1.24"},
+		{"-1.231", RoundingDown, "-1.24"},
+		{"-1.239", RoundingUp, "-1.23"},
+		{"-1.239", RoundingTowardZero, "-1.23"},
+		{"1.231", RoundingAwayFromZero, "1.24"},
+		{"1.2", RoundingAwayFromZero, "1.2"},
+		{"1.235", RoundingMode(100), "NaN"},
+	}
+
+	for _, tc := range testCases {
+		if s := FromString(tc.i).Round(2, tc.m).String(); s != tc.s {
+			t.Errorf("Round(%s, %d): expected %s, got: %s", tc.i, tc.m, tc.s, s)
+		}
+	}
+}
+
+func TestFixedDec128(t *testing.T) {
+	type testCase struct {
+		i string
+		s uint8
+		v string
+	}
+
+	testCases := [...]testCase{
+		{"1.5", 2, "1.50"},
+		{"0", 2, "0.00"},
+		{"-1.005", 2, "-1.01"},
+		{"123", 0, "123"},
+		{"0.125", 4, "0.1250"},
+	}
+
+	for _, tc := range testCases {
+		v, err := Fixed(FromString(tc.i), tc.s).Value()
+		if err != nil || v != tc.v {
+			t.Errorf("Fixed(%s, %d): expected %s, got: %v (%v)", tc.i, tc.s, tc.v, v, err)
+		}
+	}
+
+	if s := (FixedDec128{Dec128: FromString("1.005"), ColumnScale: 2, Mode: RoundingBank}).String(); s != "1.00" {
+		t.Errorf("expected 1.00, got: %s", s)
+	}
+	if v, err := (FixedDec128{Dec128: FromString("1.001"), ColumnScale: 2, Mode: RoundingUp}).Value(); err != nil || v != "1.01" {
+		t.Errorf("expected 1.01, got: %v (%v)", v, err)
+	}
+	if _, err := (FixedDec128{Dec128: FromString("1.001"), ColumnScale: 2, Mode: RoundingMode(100)}).Value(); err != state.InvalidFormat.Error() {
+		t.Errorf("expected invalid format error, got: %v", err)
+	}
+
+	if _, err := Fixed(NaN(state.DivisionByZero), 2).Value(); err != state.DivisionByZero.Error() {
+		t.Errorf("expected division by zero error, got: %v", err)
+	}
+	if _, err := Fixed(FromString("1"), MaxScale+1).Value(); err != state.ScaleOutOfRange.Error() {
+		t.Errorf("expected scale out of range error, got: %v", err)
+	}
+	if _, err := Fixed(FromString("100000000000000000000"), MaxScale).Value(); err != state.Overflow.Error() {
+		t.Errorf("expected overflow error, got: %v", err)
+	}
+
+	f := Fixed(Zero, 2)
+	if err := f.Scan("1.5"); err != nil || f.StringFixed() != "1.50" {
+		t.Errorf("expected 1.50, got: %s (%v)", f.StringFixed(), err)
+	}
+	if f.Scale() != 2 || f.ColumnScale != 2 {
+		t.Errorf("expected scale 2, got: %d %d", f.Scale(), f.ColumnScale)
+	}
+	if err := f.Scan([]byte("-2.100")); err != nil || f.StringFixed() != "-2.10" {
+		t.Errorf("expected -2.10, got: %s (%v)", f.StringFixed(), err)
+	}
+	if err := f.Scan("2.105"); err != state.RescaleToLowerScale.Error() || f.StringFixed() != "-2.10" {
+		t.Errorf("expected rescale error and unchanged value, got: %s (%v)", f.StringFixed(), err)
+	}
+	if err := f.Scan(int64(7)); err != nil || f.StringFixed() != "7.00" {
+		t.Errorf("expected 7.00, got: %s (%v)", f.StringFixed(), err)
+	}
+	if err := f.Scan("x"); err == nil {
+		t.Errorf("expected error")
+	}
+
+	// floats and rationals are rounded once to the column scale with the column rounding mode
+	scans := [...]struct {
+		src  any
+		mode RoundingMode
+		s    uint8
+		v    string
+	}{
+		{float64(-2.675), RoundingHalfAwayFromZero, 2, "-2.68"},
+		{float32(0.125), RoundingBank, 2, "0.12"},
+		{float64(1.001), RoundingUp, 2, "1.01"},
+		{float64(1.496e-18), RoundingHalfAwayFromZero, 18, "0.000000000000000001"},
+		{float64(-1.496e-18), RoundingDown, 18, "-0.000000000000000002"},
+		{big.NewRat(1, 8), RoundingHalfTowardZero, 2, "0.12"},
+		{big.NewRat(-1, 3), RoundingAwayFromZero, 2, "-0.34"},
+	}
+	for _, tc := range scans {
+		g := FixedDec128{ColumnScale: tc.s, Mode: tc.mode}
+		if err := g.Scan(tc.src); err != nil || g.StringFixed() != tc.v {
+			t.Errorf("%T(%v): expected %s, got: %s (%v)", tc.src, tc.src, tc.v, g.StringFixed(), err)
+		}
+	}
+	if err := f.Scan(math.Inf(1)); err != state.NaN.Error() || f.StringFixed() != "7.00" {
+		t.Errorf("expected NaN error and unchanged value, got: %s (%v)", f.StringFixed(), err)
+	}
+
+	type doc struct {
+		A FixedDec128
+		B FixedDec128
+	}
+
+	b, err := json.Marshal(doc{A: Fixed(FromString("1.5"), 2), B: Fixed(Zero, 3)})
+	if err != nil || string(b) != `{"A":"1.50","B":"0.000"}` {
+		t.Errorf("expected %s, got: %s (%v)", `{"A":"1.50","B":"0.000"}`, string(b), err)
+	}
+	r := doc{A: Fixed(Zero, 2), B: Fixed(Zero, 3)}
+	if err := json.Unmarshal([]byte(`{"A":"1.5","B":2}`), &r); err != nil || r.A.StringFixed() != "1.50" || r.B.StringFixed() != "2.000" {
+		t.Errorf("expected 1.50 and 2.000, got: %s %s (%v)", r.A.StringFixed(), r.B.StringFixed(), err)
+	}
+	if err := json.Unmarshal([]byte(`{"A":"1.555"}`), &r); !errors.Is(err, state.RescaleToLowerScale.Error()) {
+		t.Errorf("expected rescale error, got: %v", err)
+	}
+
+	if s, err := Fixed(FromString("-1.005"), 2).MarshalText(); err != nil || string(s) != "-1.01" {
+		t.Errorf("expected -1.01, got: %s (%v)", s, err)
+	}
+	if s, err := Fixed(Zero, 1).MarshalText(); err != nil || string(s) != "0.0" {
+		t.Errorf("expected 0.0, got: %s (%v)", s, err)
+	}
+	if err := f.UnmarshalText([]byte("4.2")); err != nil || f.StringFixed() != "4.20" {
+		t.Errorf("expected 4.20, got: %s (%v)", f.StringFixed(), err)
+	}
+}
+
+func TestFixedDec128Binary(t *testing.T) {
+	values := [...]FixedDec128{
+		{},
+		Fixed(FromString("1.50"), 2),
+		{Dec128: FromString("-12.345"), ColumnScale: 4, Mode: RoundingBank},
+		Fixed(NaN(state.Overflow), 3),
+	}
+
+	for _, v := range values {
+		b, err := v.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", v.String(), err)
+		}
+		var r FixedDec128
+		if err := r.UnmarshalBinary(b); err != nil {
+			t.Fatalf("%s: unexpected error: %v", v.String(), err)
+		}
+		if r.ColumnScale != v.ColumnScale || r.Mode != v.Mode || !r.Dec128.Equal(v.Dec128) || r.Scale() != v.Scale() {
+			t.Errorf("expected %s %d %d, got: %s %d %d", v.Dec128.String(), v.ColumnScale, v.Mode, r.Dec128.String(), r.ColumnScale, r.Mode)
+		}
+	}
+
+	type doc struct {
+		A FixedDec128
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(doc{A: FixedDec128{Dec128: FromString("1.5"), ColumnScale: 3, Mode: RoundingUp}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var r doc
+	if err := gob.NewDecoder(&buf).Decode(&r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.A.ColumnScale != 3 || r.A.Mode != RoundingUp || r.A.String() != "1.500" {
+		t.Errorf("expected 1.500 with scale 3 and mode %d, got: %s %d %d", RoundingUp, r.A.String(), r.A.ColumnScale, r.A.Mode)
+	}
+
+	for _, b := range [][]byte{nil, {2}, {20, 0, 0}, {2, 100, 0}, {2, 0}} {
+		var f FixedDec128
+		if err := f.UnmarshalBinary(b); err == nil {
+			t.Errorf("%x: expected error, got: %s", b, f.String())
+		}
+	}
+}
+
+func TestXML(t *testing.T) {
+	type doc struct {
+		XMLName xml.Name    `xml:"Doc"`
+		Ccy     string      `xml:"Ccy,attr"`
+		A       Dec128      `xml:"A,attr"`
+		B       Dec128      `xml:"B"`
+		F       FixedDec128 `xml:"F"`
+		N       NullDec128  `xml:"N,attr"`
+		M       NullDec128  `xml:"M"`
+	}
+
+	v := doc{Ccy: "EUR", A: FromString("-1.50"), B: FromString("1234.5"), F: Fixed(FromString("2.005"), 2), M: NewNullDec128(Decimal1)}
+	x := `<Doc Ccy="EUR" A="-1.5"><B>1234.5</B><F>2.01</F><M>1</M></Doc>`
+
+	b, err := xml.Marshal(v)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(b) != x {
+		t.Errorf("expected %s, got: %s", x, string(b))
+	}
+
+	r := doc{F: Fixed(Zero, 3)}
+	if err := xml.Unmarshal([]byte(`<Doc A=" 7.25 " N="3"><B>
+		-0.001
+	</B><F>2.1</F></Doc>`), &r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.A.String() != "7.25" || r.B.String() != "-0.001" || r.F.StringFixed() != "2.100" || !r.N.Valid || r.N.String() != "3" || r.M.Valid {
+		t.Errorf("unexpected values: %s %s %s %s %s", r.A.String(), r.B.String(), r.F.StringFixed(), r.N.String(), r.M.String())
+	}
+
+	if err := xml.Unmarshal([]byte(`<Doc><B>1x</B></Doc>`), &r); err == nil {
+		t.Errorf("expected error")
+	}
+	if err := xml.Unmarshal([]byte(`<Doc><F>1.2345</F></Doc>`), &r); err != state.RescaleToLowerScale.Error() {
+		t.Errorf("expected rescale error, got: %v", err)
+	}
+}
+
+func TestYAML(t *testing.T) {
+	type testCase struct {
+		d any
+		v any
+	}
+
+	testCases := [...]testCase{
+		{FromString("0"), int64(0)},
+		{FromString("-123"), int64(-123)},
+		{FromString("1.50"), 1.5},
+		{FromString("-0.001"), -0.001},
+		{FromString("12345678901234567890"), "12345678901234567890"},
+		{FromString("0.1234567890123456789"), "0.1234567890123456789"},
+		{FromString("1234567.5"), "1234567.5"},
+		{FromString("123456.5"), 123456.5},
+		{FromString("0.00001"), "0.00001"},
+		{NaN(state.DivisionByZero), "NaN"},
+		{Fixed(FromString("1.5"), 2), "1.50"},
+		{Fixed(FromString("1.5"), 1), 1.5},
+		{Fixed(FromString("7"), 0), int64(7)},
+		{NullDec128{}, nil},
+		{NewNullDec128(FromString("2.5")), 2.5},
+	}
+
+	for _, tc := range testCases {
+		v, err := tc.d.(interface{ MarshalYAML() (any, error) }).MarshalYAML()
+		if err != nil || v != tc.v {
+			t.Errorf("%v: expected %#v, got: %#v (%v)", tc.d, tc.v, v, err)
+		}
+	}
+
+	// yaml passes the scalar text when decoding into a string
+	text := func(s string) func(any) error {
+		return func(v any) error {
+			*v.(*string) = s
+			return nil
+		}
+	}
+
+	var d Dec128
+	if err := d.UnmarshalYAML(text("-1.25")); err != nil || d.String() != "-1.25" {
+		t.Errorf("expected -1.25, got: %s (%v)", d.String(), err)
+	}
+	if err := d.UnmarshalYAML(text("NaN")); err != nil || !d.IsNaN() {
+		t.Errorf("expected NaN, got: %s (%v)", d.String(), err)
+	}
+	if err := d.UnmarshalYAML(text("1e3")); err == nil {
+		t.Errorf("expected error")
+	}
+
+	f := Fixed(Zero, 2)
+	if err := f.UnmarshalYAML(text("3.1")); err != nil || f.StringFixed() != "3.10" {
+		t.Errorf("expected 3.10, got: %s (%v)", f.StringFixed(), err)
+	}
+	if err := f.UnmarshalYAML(text("3.141")); err == nil {
+		t.Errorf("expected error")
+	}
+
+	var n NullDec128
+	if err := n.UnmarshalYAML(text("4")); err != nil || !n.Valid || n.String() != "4" {
+		t.Errorf("expected 4, got: %s (%v)", n.String(), err)
+	}
+}
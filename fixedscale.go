@@ -0,0 +1,185 @@
+package dec128
+
+import (
+	"database/sql/driver"
+	"io"
+	"math/big"
+
+	"github.com/jokruger/dec128/state"
+)
+
+// FixedDec128 is a Dec128 bound to the scale of a database column, e.g. 2 for NUMERIC(18,2).
+// Its Valuer emits the value with exactly ColumnScale digits after the decimal point, and its Scanner rescales to ColumnScale.
+//
+// ColumnScale is a runtime field, not part of the type: set it (e.g. with Fixed) before scanning or decoding into a FixedDec128.
+// A zero value has ColumnScale 0, so Scan and the JSON, text, XML and YAML decoders reject any value with digits after the
+// decimal point (e.g. "1.50") with state.RescaleToLowerScale, and Scan rounds floats to integers.
+// Initialize struct fields before decoding, e.g. Row{Amount: Fixed(Zero, 2)}.
+// The binary and gob forms carry ColumnScale and Mode and restore them.
+type FixedDec128 struct {
+	Dec128
+
+	// ColumnScale is the number of digits after the decimal point of the column.
+	ColumnScale uint8
+
+	// Mode is the rounding mode used when the value has more than ColumnScale digits after the decimal point.
+	// The zero value is RoundingHalfAwayFromZero.
+	Mode RoundingMode
+}
+
+// Fixed returns d bound to the given column scale, rounded half away from zero. Set Mode to use another rounding mode.
+func Fixed(d Dec128, scale uint8) FixedDec128 {
+	return FixedDec128{Dec128: d, ColumnScale: scale}
+}
+
+// Value implements the driver.Valuer interface.
+// It returns the value rounded to ColumnScale with Mode, formatted with trailing zeros preserved (1.5 as "1.50" for scale 2).
+// It returns an error if the value is NaN or cannot be represented at ColumnScale.
+func (f FixedDec128) Value() (driver.Value, error) {
+	d := f.rounded()
+	if d.IsNaN() {
+		return nil, d.ErrorDetails()
+	}
+
+	return d.StringFixed(), nil
+}
+
+// Scan implements the sql.Scanner interface. It accepts the same sources as Dec128.Scan and rescales the value to ColumnScale.
+// Floats and *big.Rat values are rounded once to ColumnScale with Mode. For other sources it returns state.RescaleToLowerScale
+// if rescaling would drop non-zero digits. It leaves f unchanged in case of errors.
+func (f *FixedDec128) Scan(src any) error {
+	var d Dec128
+	switch v := src.(type) {
+	case float32:
+		d = scanFloat(float64(v), 32, f.ColumnScale, f.Mode)
+	case float64:
+		d = scanFloat(v, 64, f.ColumnScale, f.Mode)
+	case *big.Rat:
+		d = fromBigRat(v, f.ColumnScale, f.Mode)
+	default:
+		if err := d.Scan(src); err != nil {
+			return err
+		}
+	}
+
+	if d.IsNaN() {
+		return d.ErrorDetails()
+	}
+
+	return f.set(d)
+}
+
+// MarshalJSON implements the json.Marshaler interface. The string has exactly ColumnScale digits after the decimal point, as Value.
+// NaN is encoded as the string "NaN", as by Dec128.
+func (f FixedDec128) MarshalJSON() ([]byte, error) {
+	buf := [MaxStrLen + 2]byte{}
+	return f.appendJSON(buf[:0])
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. It accepts the same input as Dec128.UnmarshalJSON and rescales the value to ColumnScale as Scan.
+func (f *FixedDec128) UnmarshalJSON(data []byte) error {
+	var d Dec128
+	if err := d.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	return f.set(d)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface. The text has exactly ColumnScale digits after the decimal point, as Value.
+func (f FixedDec128) MarshalText() ([]byte, error) {
+	d := f.rounded()
+	if d.IsNaN() {
+		return NaNStrBytes, nil
+	}
+	buf := [MaxStrLen]byte{}
+	return d.appendStringFixed(buf[:0]), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface. It accepts the same input as Dec128.UnmarshalText and rescales the value to ColumnScale as Scan.
+func (f *FixedDec128) UnmarshalText(data []byte) error {
+	var d Dec128
+	if err := d.UnmarshalText(data); err != nil {
+		return err
+	}
+	return f.set(d)
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// The binary form is ColumnScale and Mode (one byte each), followed by the binary form of Dec128.
+func (f FixedDec128) MarshalBinary() ([]byte, error) {
+	return f.AppendBinary(make([]byte, 0, MaxBytes+2))
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface. It restores ColumnScale, Mode and the value as written by MarshalBinary.
+func (f *FixedDec128) UnmarshalBinary(data []byte) error {
+	switch {
+	case len(data) < 2:
+		return io.ErrShortBuffer
+	case data[0] > MaxScale:
+		return state.ScaleOutOfRange.Error()
+	case RoundingMode(data[1]) > RoundingAwayFromZero:
+		return state.InvalidFormat.Error()
+	}
+
+	var d Dec128
+	if err := d.UnmarshalBinary(data[2:]); err != nil {
+		return err
+	}
+	f.Dec128, f.ColumnScale, f.Mode = d, data[0], RoundingMode(data[1])
+
+	return nil
+}
+
+// AppendBinary appends the binary form of MarshalBinary to buf and returns the extended buffer.
+func (f FixedDec128) AppendBinary(buf []byte) ([]byte, error) {
+	b, err := f.Dec128.AppendBinary(append(buf, f.ColumnScale, byte(f.Mode)))
+	if err != nil {
+		return buf, err
+	}
+	return b, nil
+}
+
+// GobEncode implements the gob.GobEncoder interface for gob serialization. It uses the binary form of MarshalBinary.
+func (f FixedDec128) GobEncode() ([]byte, error) {
+	return f.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface for gob serialization.
+func (f *FixedDec128) GobDecode(data []byte) error {
+	return f.UnmarshalBinary(data)
+}
+
+// set sets f to d rescaled to ColumnScale. It returns state.RescaleToLowerScale if that would drop non-zero digits.
+func (f *FixedDec128) set(d Dec128) error {
+	r := d.Rescale(f.ColumnScale)
+	switch {
+	case r.IsNaN():
+		return r.ErrorDetails()
+	case !r.Equal(d):
+		return state.RescaleToLowerScale.Error()
+	}
+	f.Dec128 = r
+
+	return nil
+}
+
+// String returns the value with exactly ColumnScale digits after the decimal point, rounded as by Value.
+func (f FixedDec128) String() string {
+	return f.rounded().StringFixed()
+}
+
+// appendJSON appends the JSON string of the rounded value to buf.
+func (f FixedDec128) appendJSON(buf []byte) ([]byte, error) {
+	d := f.rounded()
+	if d.IsNaN() {
+		return d.appendJSON(buf, true, jsonNaNDefault)
+	}
+	buf = append(buf, '"')
+	buf = d.appendStringFixed(buf)
+	return append(buf, '"'), nil
+}
+
+// rounded returns the value rounded to ColumnScale with Mode.
+func (f FixedDec128) rounded() Dec128 {
+	return f.Dec128.Round(f.ColumnScale, f.Mode).Rescale(f.ColumnScale)
+}
@@ -0,0 +1,192 @@
+package dec128
+
+import (
+	"github.com/jokruger/dec128/state"
+	"github.com/jokruger/dec128/uint128"
+)
+
+// SignStyle defines how the sign is encoded in fixed-width implied-decimal fields.
+type SignStyle uint8
+
+const (
+	// SignNone encodes unsigned fields, negative values are not allowed.
+	SignNone SignStyle = iota
+	// SignLeading reserves the first position for a '+' or '-' sign.
+	SignLeading
+	// SignTrailing reserves the last position for a '+' or '-' sign.
+	SignTrailing
+	// SignLeadingOverpunch encodes the sign into the first digit ('{', 'A'-'I' for positive and '}', 'J'-'R' for negative values).
+	SignLeadingOverpunch
+	// SignTrailingOverpunch encodes the sign into the last digit ('{', 'A'-'I' for positive and '}', 'J'-'R' for negative values).
+	SignTrailingOverpunch
+)
+
+var (
+	overpunchPos = [...]byte{'{', 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I'}
+	overpunchNeg = [...]byte{'}', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R'}
+)
+
+// AppendFixedWidth appends the Dec128 as a zero-padded implied-decimal field of exactly width characters to buf (e.g. 123.45 with width 10 and implied scale 2 -> "0000012345").
+// It returns an error if the Dec128 is NaN, has more fractional digits than impliedScale, does not fit into width, or is negative with SignNone.
+func (d Dec128) AppendFixedWidth(buf []byte, width int, impliedScale uint8, sign SignStyle) ([]byte, error) {
+	if d.state >= state.Error {
+		return buf, d.state.Error()
+	}
+
+	neg := d.state == state.Neg && !d.coef.IsZero()
+	if neg && sign == SignNone {
+		return buf, state.NegativeInUnsignedOp.Error()
+	}
+
+	coef, s := d.impliedCoef(impliedScale)
+	if s >= state.Error {
+		return buf, s.Error()
+	}
+
+	n := width
+	if sign == SignLeading || sign == SignTrailing {
+		n--
+	}
+
+	tmp := [uint128.MaxStrLen]byte{}
+	digits := coef.StringToBuf(tmp[:])
+	if n < 1 || len(digits) > n {
+		return buf, state.Overflow.Error()
+	}
+
+	start := len(buf)
+
+	if sign == SignLeading {
+		if neg {
+			buf = append(buf, '-')
+		} else {
+			buf = append(buf, '+')
+		}
+	}
+
+	buf = appendZeros(buf, n-len(digits))
+	buf = append(buf, digits...)
+
+	switch sign {
+	case SignTrailing:
+		if neg {
+			buf = append(buf, '-')
+		} else {
+			buf = append(buf, '+')
+		}
+	case SignLeadingOverpunch:
+		buf[start] = overpunch(buf[start], neg)
+	case SignTrailingOverpunch:
+		buf[len(buf)-1] = overpunch(buf[len(buf)-1], neg)
+	}
+
+	return buf, nil
+}
+
+// FromFixedWidth creates a new Dec128 from a zero-padded implied-decimal field (e.g. "0000012345" with implied scale 2 -> 123.45).
+// The whole string is used as the field, so its length is the field width.
+// In case of errors, it returns NaN with the corresponding error.
+func FromFixedWidth[S string | []byte](s S, impliedScale uint8, sign SignStyle) Dec128 {
+	if impliedScale > MaxScale {
+		return Dec128{state: state.ScaleOutOfRange}
+	}
+
+	sz := len(s)
+	i, j := 0, sz
+	var neg bool
+
+	switch sign {
+	case SignLeading, SignTrailing:
+		if sz < 2 {
+			return Dec128{state: state.InvalidFormat}
+		}
+		k := 0
+		if sign == SignLeading {
+			i++
+		} else {
+			k = sz - 1
+			j--
+		}
+		switch s[k] {
+		case '-':
+			neg = true
+		case '+', ' ':
+		default:
+			return Dec128{state: state.InvalidFormat}
+		}
+	default:
+		if sz == 0 {
+			return Dec128{state: state.InvalidFormat}
+		}
+	}
+
+	var coef uint128.Uint128
+	var st state.State
+	for k := i; k < j; k++ {
+		c := s[k]
+		if (sign == SignLeadingOverpunch && k == 0) || (sign == SignTrailingOverpunch && k == sz-1) {
+			var n bool
+			c, n = unpunch(c)
+			neg = neg || n
+		}
+		if c < '0' || c > '9' {
+			return Dec128{state: state.InvalidFormat}
+		}
+		coef, st = coef.MulAdd64(10, uint64(c-'0'))
+		if st >= state.Error {
+			return Dec128{state: st}
+		}
+	}
+
+	if neg && !coef.IsZero() {
+		return Dec128{coef: coef, scale: impliedScale, state: state.Neg}
+	}
+
+	return Dec128{coef: coef, scale: impliedScale}
+}
+
+// impliedCoef returns the coefficient of d at the given scale without losing any digits.
+// called only when d is not NaN
+func (d Dec128) impliedCoef(scale uint8) (uint128.Uint128, state.State) {
+	switch {
+	case scale > MaxScale:
+		return uint128.Zero, state.ScaleOutOfRange
+	case d.scale > scale:
+		q, r, s := d.coef.QuoRem64(Pow10Uint64[d.scale-scale])
+		if s >= state.Error {
+			return uint128.Zero, s
+		}
+		if r != 0 {
+			return uint128.Zero, state.RescaleToLowerScale
+		}
+		return q, state.OK
+	case d.scale < scale:
+		return d.coef.Mul64(Pow10Uint64[scale-d.scale])
+	default:
+		return d.coef, state.OK
+	}
+}
+
+// overpunch returns the overpunched representation of digit c.
+func overpunch(c byte, neg bool) byte {
+	if neg {
+		return overpunchNeg[c-'0']
+	}
+	return overpunchPos[c-'0']
+}
+
+// unpunch returns the digit and sign of an overpunched character. Plain digits are returned as positive.
+func unpunch(c byte) (byte, bool) {
+	switch {
+	case c == '{':
+		return '0', false
+	case c == '}':
+		return '0', true
+	case c >= 'A' && c <= 'I':
+		return c - 'A' + '1', false
+	case c >= 'J' && c <= 'R':
+		return c - 'J' + '1', true
+	default:
+		return c, false
+	}
+}
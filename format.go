@@ -0,0 +1,98 @@
+package dec128
+
+import (
+	"github.com/jokruger/dec128/state"
+	"github.com/jokruger/dec128/uint128"
+)
+
+// FormatOptions controls the layout produced by AppendFormat.
+// The zero value produces the same output as String.
+type FormatOptions struct {
+	// Scale is the number of digits after the decimal point when Fixed is set.
+	Scale uint8
+
+	// Fixed forces exactly Scale digits after the decimal point.
+	// Values with more digits are rounded half away from zero, values with fewer digits are padded with zeros.
+	// When Fixed is not set the trailing zeros are removed.
+	Fixed bool
+
+	// MinIntDigits is the minimum number of digits in the integer part, padded with leading zeros.
+	MinIntDigits int
+
+	// GroupSeparator, if not zero, is inserted between groups of three digits in the integer part.
+	GroupSeparator byte
+
+	// DecimalSeparator replaces the default '.' if not zero.
+	DecimalSeparator byte
+
+	// PlusSign emits '+' in front of positive values and zero.
+	PlusSign bool
+
+	// NaN is the text emitted for NaN values. If empty, NaNStr is used.
+	NaN string
+}
+
+// AppendFormat appends the string representation of the Dec128 formatted according to opts to buf and returns the extended buffer.
+// It does not allocate if buf has enough capacity.
+func (d Dec128) AppendFormat(buf []byte, opts FormatOptions) []byte {
+	if d.state >= state.Error {
+		if opts.NaN != "" {
+			return append(buf, opts.NaN...)
+		}
+		return append(buf, NaNStr...)
+	}
+
+	if opts.Fixed && opts.Scale < d.scale {
+		d = d.RoundHalfAwayFromZero(opts.Scale)
+	}
+
+	tmp := [uint128.MaxStrLen]byte{}
+	coef := d.coef.StringToBuf(tmp[:])
+
+	// split coefficient digits into integer and fractional parts
+	scale := int(d.scale)
+	sz := len(coef)
+	var ipart, fpart []byte
+	var lead int // zeros between the decimal point and fpart
+	if sz > scale {
+		ipart = coef[:sz-scale]
+		fpart = coef[sz-scale:]
+	} else {
+		fpart = coef
+		lead = scale - sz
+	}
+
+	var pad int // zeros after fpart
+	if opts.Fixed {
+		pad = int(opts.Scale) - scale
+	} else {
+		fpart = trimZeros(fpart)
+		if len(fpart) == 0 {
+			lead = 0
+		}
+	}
+
+	switch {
+	case d.state == state.Neg && !d.coef.IsZero():
+		buf = append(buf, '-')
+	case opts.PlusSign:
+		buf = append(buf, '+')
+	}
+
+	buf = appendGrouped(buf, ipart, max(opts.MinIntDigits, 1), opts.GroupSeparator)
+
+	if lead+len(fpart)+pad == 0 {
+		return buf
+	}
+
+	if opts.DecimalSeparator != 0 {
+		buf = append(buf, opts.DecimalSeparator)
+	} else {
+		buf = append(buf, '.')
+	}
+	buf = appendZeros(buf, lead)
+	buf = append(buf, fpart...)
+	buf = appendZeros(buf, pad)
+
+	return buf
+}
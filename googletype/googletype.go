@@ -0,0 +1,213 @@
+// Package googletype converts Dec128 to and from the google.type.Money and google.type.Decimal protobuf messages.
+//
+// The messages are mirrored by plain structs, so the package does not depend on protobuf.
+// Copy the fields from and to the generated types.
+package googletype
+
+import (
+	"math"
+
+	"github.com/jokruger/dec128"
+	"github.com/jokruger/dec128/state"
+	"github.com/jokruger/dec128/uint128"
+)
+
+// NanosScale is the number of digits after the decimal point that can be represented by Money.Nanos.
+const NanosScale = 9
+
+// MaxNanos is the maximum absolute value of Money.Nanos.
+const MaxNanos = 999_999_999
+
+// Money mirrors google.type.Money.
+type Money struct {
+	// CurrencyCode is the three-letter ISO 4217 currency code.
+	CurrencyCode string
+
+	// Units is the whole units of the amount.
+	Units int64
+
+	// Nanos is the number of nano (10^-9) units of the amount.
+	// It must be between -999,999,999 and +999,999,999 and have the same sign as Units if Units is not zero.
+	Nanos int32
+}
+
+// Decimal mirrors google.type.Decimal.
+type Decimal struct {
+	// Value is the decimal value as a string.
+	Value string
+}
+
+// ToMoney returns d as Money in the given currency.
+// It returns an error if d is NaN, has more than 9 significant digits after the decimal point, its integer part does not fit into int64, or currency is not a three-letter upper-case code.
+func ToMoney(d dec128.Dec128, currency string) (Money, error) {
+	if _, ok := dec128.CurrencyMinorUnits(currency); !ok {
+		return Money{}, state.InvalidFormat.Error()
+	}
+	if d.IsNaN() {
+		return Money{}, d.ErrorDetails()
+	}
+
+	d = d.Canonical()
+	if d.Scale() > NanosScale {
+		return Money{}, state.RescaleToLowerScale.Error()
+	}
+
+	ipart, frac, _ := d.Coefficient().QuoRem64(dec128.Pow10Uint64[d.Scale()])
+	nanos := int64(frac * dec128.Pow10Uint64[NanosScale-d.Scale()])
+
+	if ipart.Hi != 0 || ipart.Lo > math.MaxInt64+1 || (ipart.Lo > math.MaxInt64 && !d.IsNegative()) {
+		return Money{}, state.Overflow.Error()
+	}
+	units := int64(ipart.Lo)
+
+	if d.IsNegative() {
+		units, nanos = -units, -nanos
+	}
+
+	return Money{CurrencyCode: currency, Units: units, Nanos: int32(nanos)}, nil
+}
+
+// FromMoney creates a new Dec128 from m.
+// In case m is not valid, it returns NaN with the corresponding error.
+func FromMoney(m Money) dec128.Dec128 {
+	if err := m.Validate(); err != nil {
+		return dec128.NaN(state.InvalidFormat)
+	}
+
+	units, nanos := uint64(m.Units), uint64(m.Nanos)
+	neg := m.Units < 0 || m.Nanos < 0
+	if neg {
+		units, nanos = -units, -nanos
+	}
+
+	coef, _ := uint128.FromUint64(units).MulAdd64(dec128.Pow10Uint64[NanosScale], nanos)
+
+	return dec128.New(coef, NanosScale, neg).Canonical()
+}
+
+// Validate returns an error if m breaks the rules of google.type.Money: the currency code must be a three-letter upper-case code,
+// nanos must be within ±999,999,999 and must not have the opposite sign of units.
+func (m Money) Validate() error {
+	if _, ok := dec128.CurrencyMinorUnits(m.CurrencyCode); !ok {
+		return state.InvalidFormat.Error()
+	}
+	switch {
+	case m.Nanos < -MaxNanos || m.Nanos > MaxNanos:
+		return state.InvalidFormat.Error()
+	case m.Units > 0 && m.Nanos < 0, m.Units < 0 && m.Nanos > 0:
+		return state.InvalidFormat.Error()
+	}
+	return nil
+}
+
+// ToDecimal returns d as a normalized Decimal (no explicit '+' sign, no exponent, trailing zeros removed).
+// It returns an error if d is NaN.
+func ToDecimal(d dec128.Dec128) (Decimal, error) {
+	if d.IsNaN() {
+		return Decimal{}, d.ErrorDetails()
+	}
+	return Decimal{Value: d.String()}, nil
+}
+
+// FromDecimal creates a new Dec128 from v, including values with an exponent (e.g. "2.5E+8").
+// In case v is not valid or cannot be represented exactly, it returns NaN with the corresponding error.
+func FromDecimal(v Decimal) dec128.Dec128 {
+	neg, coef, exp, st := parseDecimal(v.Value)
+	if st >= state.Error {
+		return dec128.NaN(st)
+	}
+	return dec128.FromCoefficient(neg, coef, exp)
+}
+
+// Validate returns an error if the value does not follow the google.type.Decimal grammar:
+//
+//	DecimalString = [Sign] Significand [Exponent];
+//	Sign = '+' | '-';
+//	Significand = Digits ['.'] [Digits] | [Digits] '.' Digits;
+//	Exponent = ('e' | 'E') [Sign] Digits;
+//
+// It only checks the syntax, the value can still be out of the Dec128 range.
+func (v Decimal) Validate() error {
+	if _, _, _, st := parseDecimal(v.Value); st == state.InvalidFormat {
+		return st.Error()
+	}
+	return nil
+}
+
+// parseDecimal returns the sign, coefficient and exponent of a google.type.Decimal string.
+func parseDecimal(s string) (bool, uint128.Uint128, int, state.State) {
+	sz := len(s)
+	i := 0
+
+	var neg bool
+	if i < sz && (s[i] == '+' || s[i] == '-') {
+		neg = s[i] == '-'
+		i++
+	}
+
+	var coef uint128.Uint128
+	var st state.State
+	var digits, frac int
+	var point bool
+	for ; i < sz; i++ {
+		c := s[i]
+		if c == '.' {
+			if point {
+				return false, uint128.Zero, 0, state.InvalidFormat
+			}
+			point = true
+			continue
+		}
+		if c < '0' || c > '9' {
+			break
+		}
+		digits++
+		if point {
+			frac++
+		}
+		if st < state.Error {
+			coef, st = coef.MulAdd64(10, uint64(c-'0'))
+		}
+	}
+
+	if digits == 0 {
+		return false, uint128.Zero, 0, state.InvalidFormat
+	}
+
+	var exp int
+	if i < sz {
+		if s[i] != 'e' && s[i] != 'E' {
+			return false, uint128.Zero, 0, state.InvalidFormat
+		}
+		i++
+
+		var eneg bool
+		if i < sz && (s[i] == '+' || s[i] == '-') {
+			eneg = s[i] == '-'
+			i++
+		}
+		if i == sz {
+			return false, uint128.Zero, 0, state.InvalidFormat
+		}
+
+		for ; i < sz; i++ {
+			c := s[i]
+			if c < '0' || c > '9' {
+				return false, uint128.Zero, 0, state.InvalidFormat
+			}
+			// large exponents are out of range anyway, stop growing to avoid overflow
+			if exp < 1_000_000 {
+				exp = exp*10 + int(c-'0')
+			}
+		}
+		if eneg {
+			exp = -exp
+		}
+	}
+
+	if st >= state.Error {
+		return false, uint128.Zero, 0, st
+	}
+
+	return neg, coef, exp - frac, state.OK
+}
@@ -0,0 +1,171 @@
+package googletype
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jokruger/dec128"
+	"github.com/jokruger/dec128/state"
+)
+
+func TestMoney(t *testing.T) {
+	type testCase struct {
+		i     string
+		units int64
+		nanos int32
+	}
+
+	testCases := [...]testCase{
+		{"0", 0, 0},
+		{"1", 1, 0},
+		{"-1", -1, 0},
+		{"1.75", 1, 750_000_000},
+		{"-1.75", -1, -750_000_000},
+		{"-0.75", 0, -750_000_000},
+		{"0.000000001", 0, 1},
+		{"12.3450000000", 12, 345_000_000},
+		{"9223372036854775807.999999999", math.MaxInt64, MaxNanos},
+		{"-9223372036854775808.999999999", math.MinInt64, -MaxNanos},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.i, func(t *testing.T) {
+			d := dec128.FromString(tc.i)
+
+			m, err := ToMoney(d, "USD")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if m.Units != tc.units || m.Nanos != tc.nanos || m.CurrencyCode != "USD" {
+				t.Errorf("expected %d/%d, got %d/%d", tc.units, tc.nanos, m.Units, m.Nanos)
+			}
+			if err := m.Validate(); err != nil {
+				t.Errorf("unexpected validation error: %v", err)
+			}
+			if r := FromMoney(m); !r.Equal(d) {
+				t.Errorf("expected %s, got %s", d.String(), r.String())
+			}
+		})
+	}
+}
+
+func TestMoneyErrors(t *testing.T) {
+	type testCase struct {
+		i string
+		c string
+		e error
+	}
+
+	testCases := [...]testCase{
+		{"1.0000000001", "USD", state.RescaleToLowerScale.Error()},
+		{"9223372036854775808", "USD", state.Overflow.Error()},
+		{"-9223372036854775809", "USD", state.Overflow.Error()},
+		{"1", "usd", state.InvalidFormat.Error()},
+		{"1", "", state.InvalidFormat.Error()},
+		{"abc", "USD", state.InvalidFormat.Error()},
+	}
+
+	for _, tc := range testCases {
+		if _, err := ToMoney(dec128.FromString(tc.i), tc.c); err != tc.e {
+			t.Errorf("%s %s: expected error %v, got %v", tc.i, tc.c, tc.e, err)
+		}
+	}
+
+	invalid := [...]Money{
+		{"USD", 1, -1},
+		{"USD", -1, 1},
+		{"USD", 0, MaxNanos + 1},
+		{"USD", 0, -MaxNanos - 1},
+		{"US", 1, 0},
+	}
+
+	for _, m := range invalid {
+		if err := m.Validate(); err != state.InvalidFormat.Error() {
+			t.Errorf("%v: expected error %v, got %v", m, state.InvalidFormat.Error(), err)
+		}
+		if d := FromMoney(m); !d.IsNaN() {
+			t.Errorf("%v: expected NaN, got %s", m, d.String())
+		}
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	type testCase struct {
+		i string
+		s string
+	}
+
+	testCases := [...]testCase{
+		{"0", "0"},
+		{"+2.5", "2.5"},
+		{"-2.5", "-2.5"},
+		{".5", "0.5"},
+		{"5.", "5"},
+		{"2.5e8", "250000000"},
+		{"2.5E+8", "250000000"},
+		{"2.5E0", "2.5"},
+		{"2.5E-1", "0.25"},
+		{"-0.00", "0"},
+		{"1E-19", "0.0000000000000000001"},
+		{"1000E-22", "0.0000000000000000001"},
+		{"0E+100", "0"},
+		{"007.50", "7.5"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.i, func(t *testing.T) {
+			v := Decimal{Value: tc.i}
+			if err := v.Validate(); err != nil {
+				t.Fatalf("unexpected validation error: %v", err)
+			}
+			d := FromDecimal(v)
+			if d.String() != tc.s {
+				t.Errorf("expected %s, got %s", tc.s, d.String())
+			}
+			r, err := ToDecimal(d)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if r.Value != tc.s {
+				t.Errorf("expected %s, got %s", tc.s, r.Value)
+			}
+		})
+	}
+
+	invalid := [...]string{"", "+", "-", ".", "1..2", "1.2.3", "e5", ".e5", "1e", "1e+", "1e5.5", "1,5", "1 000", " 1", "NaN", "Infinity", "0x10", "1E5x"}
+
+	for _, s := range invalid {
+		v := Decimal{Value: s}
+		if err := v.Validate(); err != state.InvalidFormat.Error() {
+			t.Errorf("%q: expected error %v, got %v", s, state.InvalidFormat.Error(), err)
+		}
+		if d := FromDecimal(v); d.ErrorDetails() != state.InvalidFormat.Error() {
+			t.Errorf("%q: expected NaN, got %s", s, d.String())
+		}
+	}
+
+	// valid syntax, but out of range
+	outOfRange := [...]struct {
+		s string
+		e error
+	}{
+		{"1E+39", state.Overflow.Error()},
+		{"1E+1000000000000", state.Overflow.Error()},
+		{"1E-20", state.ScaleOutOfRange.Error()},
+		{"1234567890123456789012345678901234567890", state.Overflow.Error()},
+	}
+
+	for _, tc := range outOfRange {
+		v := Decimal{Value: tc.s}
+		if err := v.Validate(); err != nil {
+			t.Errorf("%q: unexpected validation error: %v", tc.s, err)
+		}
+		if d := FromDecimal(v); d.ErrorDetails() != tc.e {
+			t.Errorf("%q: expected error %v, got %v", tc.s, tc.e, d.ErrorDetails())
+		}
+	}
+
+	if _, err := ToDecimal(dec128.NaN(state.DivisionByZero)); err != state.DivisionByZero.Error() {
+		t.Errorf("expected error %v, got %v", state.DivisionByZero.Error(), err)
+	}
+}
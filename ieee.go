@@ -0,0 +1,262 @@
+package dec128
+
+import (
+	"github.com/jokruger/dec128/internal/bid"
+	"github.com/jokruger/dec128/state"
+	"github.com/jokruger/dec128/uint128"
+)
+
+// IEEE 754-2008 decimal128 interchange format parameters.
+const (
+	// IEEEDecimal128Digits is the number of significant digits of the decimal128 format.
+	IEEEDecimal128Digits = 34
+
+	// IEEEDecimal128Bias is the exponent bias of the decimal128 format.
+	IEEEDecimal128Bias = bid.Bias
+
+	// IEEEDecimal128MaxExp is the maximum (unbiased) exponent of the decimal128 format.
+	IEEEDecimal128MaxExp = 6111
+
+	// IEEEDecimal128MinExp is the minimum (unbiased) exponent of the decimal128 format.
+	IEEEDecimal128MinExp = -IEEEDecimal128Bias
+)
+
+var (
+	// quiet NaN and infinity combination fields (bits 126..122)
+	ieeeNaN = uint128.Uint128{Hi: 0x7c00000000000000}
+	ieeeInf = uint128.Uint128{Hi: 0x7800000000000000}
+)
+
+// ToIEEEDecimal128BID returns the Dec128 encoded as IEEE 754-2008 decimal128 in the binary integer decimal (BID) format.
+// The bytes are in big-endian order (sign bit first). NaN is encoded as quiet NaN.
+// It returns an error if the Dec128 has more than 34 significant digits and cannot be represented exactly.
+func (d Dec128) ToIEEEDecimal128BID() ([16]byte, error) {
+	u, err := d.toIEEEDecimal128(false)
+	if err != nil {
+		return [16]byte{}, err
+	}
+	return u.BytesBigEndian(), nil
+}
+
+// ToIEEEDecimal128DPD returns the Dec128 encoded as IEEE 754-2008 decimal128 in the densely packed decimal (DPD) format.
+// The bytes are in big-endian order (sign bit first). NaN is encoded as quiet NaN.
+// It returns an error if the Dec128 has more than 34 significant digits and cannot be represented exactly.
+func (d Dec128) ToIEEEDecimal128DPD() ([16]byte, error) {
+	u, err := d.toIEEEDecimal128(true)
+	if err != nil {
+		return [16]byte{}, err
+	}
+	return u.BytesBigEndian(), nil
+}
+
+// FromIEEEDecimal128BID creates a new Dec128 from IEEE 754-2008 decimal128 in the binary integer decimal (BID) format in big-endian order.
+// NaN is decoded as NaN and infinity as NaN with overflow error.
+// In case the value cannot be represented exactly (coefficient overflow or scale above MaxScale), it returns NaN with the corresponding error.
+func FromIEEEDecimal128BID(b [16]byte) Dec128 {
+	return fromIEEEDecimal128(uint128.FromBytesBigEndian(b), false)
+}
+
+// FromIEEEDecimal128DPD creates a new Dec128 from IEEE 754-2008 decimal128 in the densely packed decimal (DPD) format in big-endian order.
+// NaN is decoded as NaN and infinity as NaN with overflow error.
+// In case the value cannot be represented exactly (coefficient overflow or scale above MaxScale), it returns NaN with the corresponding error.
+func FromIEEEDecimal128DPD(b [16]byte) Dec128 {
+	return fromIEEEDecimal128(uint128.FromBytesBigEndian(b), true)
+}
+
+// IEEEDecimal128 returns the sign, coefficient and exponent of the Dec128 as an IEEE 754-2008 decimal128 finite number.
+// Trailing zeros are moved into the exponent only when the coefficient has more than 34 digits.
+// It returns an error if the Dec128 is NaN or cannot be represented exactly.
+func (d Dec128) IEEEDecimal128() (bool, uint128.Uint128, int, error) {
+	if d.state >= state.Error {
+		return false, uint128.Zero, 0, d.state.Error()
+	}
+
+	coef := d.coef
+	exp := -int(d.scale)
+	for coef.Compare(bid.MaxCoef) > 0 {
+		q, r, _ := coef.QuoRem64(10)
+		if r != 0 {
+			return false, uint128.Zero, 0, state.Overflow.Error()
+		}
+		coef = q
+		exp++
+	}
+
+	return d.state == state.Neg && !coef.IsZero(), coef, exp, nil
+}
+
+// FromCoefficient creates a new Dec128 with the value coef * 10^exp, negated if neg is true.
+// Trailing zeros of the coefficient are removed if the exponent is below -MaxScale.
+// In case the value cannot be represented exactly, it returns NaN with the corresponding error.
+func FromCoefficient(neg bool, coef uint128.Uint128, exp int) Dec128 {
+	if coef.IsZero() {
+		return Dec128{scale: uint8(min(max(-exp, 0), int(MaxScale)))}
+	}
+
+	var st state.State
+	if neg {
+		st = state.Neg
+	}
+
+	if exp > 0 {
+		if exp >= len(Pow10Uint128) {
+			return Dec128{state: state.Overflow}
+		}
+		c, s := coef.Mul(Pow10Uint128[exp])
+		if s >= state.Error {
+			return Dec128{state: s}
+		}
+		return Dec128{coef: c, state: st}
+	}
+
+	for -exp > int(MaxScale) {
+		q, r, _ := coef.QuoRem64(10)
+		if r != 0 {
+			return Dec128{state: state.ScaleOutOfRange}
+		}
+		coef = q
+		exp++
+	}
+
+	return Dec128{coef: coef, scale: uint8(-exp), state: st}
+}
+
+func (d Dec128) toIEEEDecimal128(dpd bool) (uint128.Uint128, error) {
+	if d.state >= state.Error {
+		return ieeeNaN, nil
+	}
+
+	neg, coef, exp, err := d.IEEEDecimal128()
+	if err != nil {
+		return uint128.Zero, err
+	}
+
+	if exp > IEEEDecimal128MaxExp {
+		return uint128.Zero, state.Overflow.Error()
+	}
+
+	e := uint64(exp + IEEEDecimal128Bias)
+	var u uint128.Uint128
+
+	if dpd {
+		// leading digit goes into the combination field, the remaining 33 digits into 11 declets
+		ld, rest, _ := coef.QuoRem(Pow10Uint128[IEEEDecimal128Digits-1])
+		for i := 0; !rest.IsZero(); i++ {
+			var r uint64
+			rest, r, _ = rest.QuoRem64(1000)
+			u = u.Or(uint128.FromUint64(dpdEncode(r)).Lsh(uint(10 * i)))
+		}
+		var g uint64
+		if ld.Lo <= 7 {
+			g = (e>>12)<<3 | ld.Lo
+		} else {
+			g = 0b11000 | (e>>12)<<1 | ld.Lo&1
+		}
+		u.Hi |= g<<58 | (e&0xfff)<<46
+	} else {
+		u = coef
+		u.Hi |= e << 49
+	}
+
+	if neg {
+		u.Hi |= 1 << 63
+	}
+
+	return u, nil
+}
+
+func fromIEEEDecimal128(u uint128.Uint128, dpd bool) Dec128 {
+	if !dpd {
+		neg, coef, exp, s := bid.Split(u)
+		if s >= state.Error {
+			return Dec128{state: s}
+		}
+		return FromCoefficient(neg, coef, exp)
+	}
+
+	neg := u.Hi>>63 == 1
+	g := (u.Hi >> 58) & 0b11111
+
+	switch {
+	case g == 0b11111:
+		return Dec128{state: state.NaN}
+	case g == 0b11110:
+		return Dec128{state: state.Overflow}
+	}
+
+	var e, ld uint64
+	if g>>3 == 0b11 {
+		e = (g >> 1) & 0b11
+		ld = 8 + g&1
+	} else {
+		e = g >> 3
+		ld = g & 0b111
+	}
+	e = e<<12 | (u.Hi>>46)&0xfff
+
+	coef := uint128.FromUint64(ld)
+	for i := 10; i >= 0; i-- {
+		declet := u.Rsh(uint(10*i)).Lo & 0x3ff
+		coef, _ = coef.MulAdd64(1000, dpdDecode(declet))
+	}
+
+	if coef.Compare(bid.MaxCoef) > 0 {
+		coef = uint128.Zero
+	}
+
+	return FromCoefficient(neg, coef, int(e)-IEEEDecimal128Bias)
+}
+
+// dpdEncode returns the densely packed decimal declet of 0 <= n < 1000.
+func dpdEncode(n uint64) uint64 {
+	d2, d1, d0 := n/100, n/10%10, n%10
+
+	switch (d2>>3)<<2 | (d1>>3)<<1 | d0>>3 {
+	case 0b000:
+		return d2<<7 | d1<<4 | d0
+	case 0b001:
+		return d2<<7 | d1<<4 | 0b1000 | d0&1
+	case 0b010:
+		return d2<<7 | (d0>>1&3)<<5 | (d1&1)<<4 | 0b1010 | d0&1
+	case 0b100:
+		return (d0>>1&3)<<8 | (d2&1)<<7 | d1<<4 | 0b1100 | d0&1
+	case 0b110:
+		return (d0>>1&3)<<8 | (d2&1)<<7 | (d1&1)<<4 | 0b1110 | d0&1
+	case 0b101:
+		return (d1>>1&3)<<8 | (d2&1)<<7 | 0b01<<5 | (d1&1)<<4 | 0b1110 | d0&1
+	case 0b011:
+		return d2<<7 | 0b10<<5 | (d1&1)<<4 | 0b1110 | d0&1
+	default:
+		return (d2&1)<<7 | 0b11<<5 | (d1&1)<<4 | 0b1110 | d0&1
+	}
+}
+
+// dpdDecode returns the value 0 <= n < 1000 of the densely packed decimal declet.
+// Non-canonical declets are decoded as defined by IEEE 754-2008.
+func dpdDecode(b uint64) uint64 {
+	p, q, r := b>>9&1, b>>8&1, b>>7&1
+	s, t, u := b>>6&1, b>>5&1, b>>4&1
+	v, w, x, y := b>>3&1, b>>2&1, b>>1&1, b&1
+
+	var d2, d1, d0 uint64
+	switch {
+	case v == 0:
+		d2, d1, d0 = p<<2|q<<1|r, s<<2|t<<1|u, w<<2|x<<1|y
+	case w == 0 && x == 0:
+		d2, d1, d0 = p<<2|q<<1|r, s<<2|t<<1|u, 8|y
+	case w == 0 && x == 1:
+		d2, d1, d0 = p<<2|q<<1|r, 8|u, s<<2|t<<1|y
+	case w == 1 && x == 0:
+		d2, d1, d0 = 8|r, s<<2|t<<1|u, p<<2|q<<1|y
+	case s == 0 && t == 0:
+		d2, d1, d0 = 8|r, 8|u, p<<2|q<<1|y
+	case s == 0 && t == 1:
+		d2, d1, d0 = 8|r, p<<2|q<<1|u, 8|y
+	case s == 1 && t == 0:
+		d2, d1, d0 = p<<2|q<<1|r, 8|u, 8|y
+	default:
+		d2, d1, d0 = 8|r, 8|u, 8|y
+	}
+
+	return d2*100 + d1*10 + d0
+}
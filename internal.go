@@ -241,6 +241,16 @@ func (d Dec128) appendString(sb []byte) ([]byte, bool) {
 	return sb, true
 }
 
+// appendStringFixed appends the string representation of the decimal with the trailing zeros preserved to sb (see StringFixed).
+// called only when d is not NaN
+func (d Dec128) appendStringFixed(sb []byte) []byte {
+	if d.coef.IsZero() {
+		return append(sb, zeroStrs[d.scale]...)
+	}
+	sb, _ = d.appendString(sb)
+	return sb
+}
+
 func trimTrailingZeros(sb []byte) []byte {
 	i := len(sb)
 
@@ -304,3 +314,43 @@ func (d Dec128) trySqrt() (Dec128, bool) {
 
 	return Dec128{coef: x, scale: scale}, true
 }
+
+// appendGrouped appends digits left padded with zeros to at least minDigits, inserting sep between groups of three if sep is not zero.
+func appendGrouped(buf []byte, digits []byte, minDigits int, sep byte) []byte {
+	n := max(len(digits), minDigits)
+	lead := n - len(digits)
+
+	for i := range n {
+		if sep != 0 && i > 0 && (n-i)%3 == 0 {
+			buf = append(buf, sep)
+		}
+		if i < lead {
+			buf = append(buf, '0')
+		} else {
+			buf = append(buf, digits[i-lead])
+		}
+	}
+
+	return buf
+}
+
+// appendZeros appends n zero characters to buf.
+func appendZeros(buf []byte, n int) []byte {
+	for n > len(zeros) {
+		buf = append(buf, zeros[:]...)
+		n -= len(zeros)
+	}
+	if n > 0 {
+		buf = append(buf, zeros[:n]...)
+	}
+	return buf
+}
+
+// trimZeros removes trailing zero digits from sb.
+func trimZeros(sb []byte) []byte {
+	i := len(sb)
+	for i > 0 && sb[i-1] == '0' {
+		i--
+	}
+	return sb[:i]
+}
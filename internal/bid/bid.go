@@ -0,0 +1,43 @@
+// Package bid decodes IEEE 754-2008 decimal128 values in the binary integer decimal (BID) format.
+// It is shared by the dec128 and bson packages.
+package bid
+
+import (
+	"github.com/jokruger/dec128/state"
+	"github.com/jokruger/dec128/uint128"
+)
+
+// Bias is the exponent bias of the decimal128 format.
+const Bias = 6176
+
+// MaxCoef is 10^34 - 1, the largest canonical decimal128 coefficient.
+var MaxCoef = uint128.Uint128{Lo: 0x378d8e63ffffffff, Hi: 0x1ed09bead87c0}
+
+// Split returns the sign, coefficient and exponent of u, a decimal128 in the BID format.
+// Non-canonical coefficients (10^34 or more) are decoded as zero.
+// For NaN it returns state.NaN and for infinity state.Overflow.
+func Split(u uint128.Uint128) (bool, uint128.Uint128, int, state.State) {
+	neg := u.Hi>>63 == 1
+
+	var e uint64
+	var coef uint128.Uint128
+
+	switch g := (u.Hi >> 58) & 0b11111; {
+	case g == 0b11111:
+		return false, uint128.Zero, 0, state.NaN
+	case g == 0b11110:
+		return false, uint128.Zero, 0, state.Overflow
+	case g>>3 == 0b11:
+		// coefficient would be 2^113 or more, which is non-canonical and treated as zero
+		e = (u.Hi >> 47) & 0x3fff
+	default:
+		e = (u.Hi >> 49) & 0x3fff
+		coef = uint128.Uint128{Lo: u.Lo, Hi: u.Hi & (1<<49 - 1)}
+	}
+
+	if coef.Compare(MaxCoef) > 0 {
+		coef = uint128.Zero
+	}
+
+	return neg, coef, int(e) - Bias, state.OK
+}
@@ -0,0 +1,39 @@
+package bid
+
+import (
+	"testing"
+
+	"github.com/jokruger/dec128/state"
+	"github.com/jokruger/dec128/uint128"
+)
+
+func TestMaxCoef(t *testing.T) {
+	if s := MaxCoef.String(); s != "9999999999999999999999999999999999" {
+		t.Errorf("expected 10^34 - 1, got: %s", s)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	type testCase struct {
+		u    uint128.Uint128
+		neg  bool
+		coef uint64
+		exp  int
+		s    state.State
+	}
+
+	testCases := [...]testCase{
+		{uint128.Uint128{Lo: 1, Hi: 0x3040000000000000}, false, 1, 0, state.OK},
+		{uint128.Uint128{Lo: 1, Hi: 0xb03e000000000000}, true, 1, -1, state.OK},
+		{uint128.Uint128{Lo: 0xffffffffffffffff, Hi: 0x6c1fffffffffffff}, false, 0, 31, state.OK}, // non-canonical coefficient of 2^113 or more
+		{uint128.Uint128{Hi: 0x7c00000000000000}, false, 0, 0, state.NaN},
+		{uint128.Uint128{Hi: 0xf800000000000000}, false, 0, 0, state.Overflow},
+	}
+
+	for _, tc := range testCases {
+		neg, coef, exp, s := Split(tc.u)
+		if neg != tc.neg || !coef.Equal(uint128.FromUint64(tc.coef)) || exp != tc.exp || s != tc.s {
+			t.Errorf("%x %x: expected %v %d %d %d, got: %v %s %d %d", tc.u.Hi, tc.u.Lo, tc.neg, tc.coef, tc.exp, tc.s, neg, coef.String(), exp, s)
+		}
+	}
+}
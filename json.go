@@ -6,33 +6,134 @@ import (
 	"github.com/jokruger/dec128/state"
 )
 
+// JSONNaN is the NaN mode type parameter of JSONString and JSONNumberNaN.
+// It is implemented by JSONNaNString, JSONNaNNull and JSONNaNError.
+type JSONNaN interface {
+	jsonNaN() jsonNaN
+}
+
+// JSONNaNString encodes NaN as the string "NaN" and decodes the string "NaN" as NaN.
+type JSONNaNString struct{}
+
+// JSONNaNNull encodes NaN as null and decodes null as NaN, so that a NaN is not read back as 0.
+// Use NullDec128 for values that may be null.
+type JSONNaNNull struct{}
+
+// JSONNaNError makes MarshalJSON return the error of a NaN value. The string "NaN" is rejected by UnmarshalJSON.
+type JSONNaNError struct{}
+
+// jsonNaN is the NaN mode of the JSON encoders.
+type jsonNaN uint8
+
+const (
+	// "NaN" when encoding, an error when decoding, as Dec128 and JSONNumber
+	jsonNaNDefault jsonNaN = iota
+	jsonNaNString
+	jsonNaNNull
+	jsonNaNError
+)
+
+func (JSONNaNString) jsonNaN() jsonNaN { return jsonNaNString }
+func (JSONNaNNull) jsonNaN() jsonNaN   { return jsonNaNNull }
+func (JSONNaNError) jsonNaN() jsonNaN  { return jsonNaNError }
+
+// JSONNumber is a Dec128 that is encoded as a bare JSON number (1.5) instead of a string ("1.5").
+// Use it as the field type to choose the format per field, and convert with JSONNumber(d) and Dec128(n).
+// NaN is encoded as the string "NaN", as by Dec128; use JSONNumberNaN to choose another NaN mode.
+// With encoding/json/v2 it is encoded as a string if the `string` tag option or the json.StringifyNumbers option is set;
+// the `format` tag option is not supported by Dec128 or JSONNumber.
+type JSONNumber Dec128
+
+// JSONString is a Dec128 that is encoded as a JSON string, as by Dec128, with NaN encoded and decoded according to the mode M.
+// Use it as the field type (e.g. JSONString[JSONNaNNull]) to choose the NaN mode per field, and convert with JSONString[M](d) and Dec128(s).
+type JSONString[M JSONNaN] Dec128
+
+// JSONNumberNaN is a JSONNumber with NaN encoded and decoded according to the mode M (e.g. JSONNumberNaN[JSONNaNError]).
+type JSONNumberNaN[M JSONNaN] Dec128
+
 // MarshalJSON implements the json.Marshaler interface.
+// NaN is encoded as the string "NaN".
 func (d Dec128) MarshalJSON() ([]byte, error) {
-	switch {
-	case d.state >= state.Error:
-		return NaNJsonStrBytes, nil
-	case d.IsZero():
-		return ZeroJsonStrBytes, nil
-	}
-
 	buf := [MaxStrLen + 2]byte{}
-	buf[0] = '"'
-	sb, trim := d.appendString(buf[:1])
-	if trim {
-		sb = trimTrailingZeros(sb)
-	}
-	return append(sb, '"'), nil
+	return d.appendJSON(buf[:0], true, jsonNaNDefault)
 }
 
 var nullValue = []byte("null")
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
+// It accepts both strings ("1.5") and numbers (1.5). null is decoded as zero, and the string "NaN" is rejected;
+// use JSONString or JSONNumberNaN to decode NaN.
 func (d *Dec128) UnmarshalJSON(data []byte) error {
+	return d.unmarshalJSON(data, jsonNaNDefault)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (n JSONNumber) MarshalJSON() ([]byte, error) {
+	buf := [MaxStrLen]byte{}
+	return Dec128(n).appendJSON(buf[:0], false, jsonNaNDefault)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. It accepts the same input as Dec128.UnmarshalJSON.
+func (n *JSONNumber) UnmarshalJSON(data []byte) error {
+	return (*Dec128)(n).UnmarshalJSON(data)
+}
+
+// String returns the string representation of the number.
+func (n JSONNumber) String() string {
+	return Dec128(n).String()
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s JSONString[M]) MarshalJSON() ([]byte, error) {
+	var m M
+	buf := [MaxStrLen + 2]byte{}
+	return Dec128(s).appendJSON(buf[:0], true, m.jsonNaN())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. It accepts the same input as Dec128.UnmarshalJSON, and NaN according to M.
+func (s *JSONString[M]) UnmarshalJSON(data []byte) error {
+	var m M
+	return (*Dec128)(s).unmarshalJSON(data, m.jsonNaN())
+}
+
+// String returns the string representation of the number.
+func (s JSONString[M]) String() string {
+	return Dec128(s).String()
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (n JSONNumberNaN[M]) MarshalJSON() ([]byte, error) {
+	var m M
+	buf := [MaxStrLen]byte{}
+	return Dec128(n).appendJSON(buf[:0], false, m.jsonNaN())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. It accepts the same input as Dec128.UnmarshalJSON, and NaN according to M.
+func (n *JSONNumberNaN[M]) UnmarshalJSON(data []byte) error {
+	var m M
+	return (*Dec128)(n).unmarshalJSON(data, m.jsonNaN())
+}
+
+// String returns the string representation of the number.
+func (n JSONNumberNaN[M]) String() string {
+	return Dec128(n).String()
+}
+
+// unmarshalJSON decodes a JSON string or number into d, with NaN decoded according to nan.
+func (d *Dec128) unmarshalJSON(data []byte, nan jsonNaN) error {
 	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
 		data = data[1 : len(data)-1]
+		if nan == jsonNaNString && bytes.Equal(data, NaNStrBytes) {
+			*d = NaN(state.NaN)
+			return nil
+		}
 	}
 
 	if len(data) == 0 || bytes.Equal(data, nullValue) {
+		if nan == jsonNaNNull && len(data) != 0 {
+			*d = NaN(state.NaN)
+			return nil
+		}
 		*d = Zero
 		return nil
 	}
@@ -45,3 +146,38 @@ func (d *Dec128) UnmarshalJSON(data []byte) error {
 
 	return nil
 }
+
+// appendJSON appends the JSON representation of d to buf, as a string if quote is set and as a number otherwise.
+// NaN is encoded according to nan.
+func (d Dec128) appendJSON(buf []byte, quote bool, nan jsonNaN) ([]byte, error) {
+	if d.state >= state.Error {
+		switch nan {
+		case jsonNaNNull:
+			return append(buf, nullValue...), nil
+		case jsonNaNError:
+			return buf, d.state.Error()
+		default:
+			return append(buf, NaNJsonStrBytes...), nil
+		}
+	}
+
+	if quote {
+		buf = append(buf, '"')
+	}
+
+	if d.coef.IsZero() {
+		buf = append(buf, '0')
+	} else {
+		var trim bool
+		buf, trim = d.appendString(buf)
+		if trim {
+			buf = trimTrailingZeros(buf)
+		}
+	}
+
+	if quote {
+		buf = append(buf, '"')
+	}
+
+	return buf, nil
+}
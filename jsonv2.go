@@ -0,0 +1,155 @@
+//go:build go1.27 && goexperiment.jsonv2
+
+// encoding/json/v2 first shipped behind the jsonv2 experiment in Go 1.25, but it only became part of the Go 1 API,
+// with the method signatures and options used here, in Go 1.27. The go1.27 constraint keeps this file out of builds
+// with the earlier, unstable experiment, and lets vet accept it in a module that declares an older go version.
+
+package dec128
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	json "encoding/json/v2"
+
+	"github.com/jokruger/dec128/state"
+)
+
+// MarshalJSONTo implements the json.MarshalerTo interface of encoding/json/v2.
+// It writes the same JSON string as MarshalJSON directly into the encoder buffer.
+//
+// The `format` tag option (e.g. `json:",format:number"`) is not supported: encoding/json/v2 does not pass it to marshal methods.
+// Choose the format per field with the field type instead: Dec128 for a string, JSONNumber for a bare number,
+// and JSONNumber with the `string` tag option or the json.StringifyNumbers option for a string.
+func (d Dec128) MarshalJSONTo(enc *jsontext.Encoder) error {
+	b, err := d.appendJSON(enc.AvailableBuffer(), true, jsonNaNDefault)
+	if err != nil {
+		return err
+	}
+	return enc.WriteValue(b)
+}
+
+// UnmarshalJSONFrom implements the json.UnmarshalerFrom interface of encoding/json/v2.
+// It accepts the same input as UnmarshalJSON.
+func (d *Dec128) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return d.unmarshalJSONFrom(dec, jsonNaNDefault)
+}
+
+// unmarshalJSONFrom reads a JSON string, number or null from dec into d, with NaN decoded according to nan.
+func (d *Dec128) unmarshalJSONFrom(dec *jsontext.Decoder, nan jsonNaN) error {
+	v, err := dec.ReadValue()
+	if err != nil {
+		return err
+	}
+
+	switch v.Kind() {
+	case '"':
+		// strings with escape sequences are unquoted into a temporary buffer
+		if bytes.IndexByte(v, '\\') >= 0 {
+			buf := [MaxStrLen + 2]byte{'"'}
+			u, err := jsontext.AppendUnquote(buf[:1], v)
+			if err != nil {
+				return err
+			}
+			v = append(u, '"')
+		}
+	case '0', 'n':
+	default:
+		return state.InvalidFormat.Error()
+	}
+
+	return d.unmarshalJSON(v, nan)
+}
+
+// MarshalJSONTo implements the json.MarshalerTo interface of encoding/json/v2.
+// The number is written as a bare JSON number, or as a JSON string if the json.StringifyNumbers option or the `string` tag option is set.
+func (n JSONNumber) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return Dec128(n).marshalJSONNumberTo(enc, jsonNaNDefault)
+}
+
+// UnmarshalJSONFrom implements the json.UnmarshalerFrom interface of encoding/json/v2.
+// It accepts both JSON numbers and strings.
+func (n *JSONNumber) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return (*Dec128)(n).UnmarshalJSONFrom(dec)
+}
+
+// MarshalJSONTo implements the json.MarshalerTo interface of encoding/json/v2. It writes the same JSON string as MarshalJSON.
+func (s JSONString[M]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	var m M
+	b, err := Dec128(s).appendJSON(enc.AvailableBuffer(), true, m.jsonNaN())
+	if err != nil {
+		return err
+	}
+	return enc.WriteValue(b)
+}
+
+// UnmarshalJSONFrom implements the json.UnmarshalerFrom interface of encoding/json/v2. It accepts the same input as UnmarshalJSON.
+func (s *JSONString[M]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	var m M
+	return (*Dec128)(s).unmarshalJSONFrom(dec, m.jsonNaN())
+}
+
+// MarshalJSONTo implements the json.MarshalerTo interface of encoding/json/v2. It writes the number as JSONNumber does.
+func (n JSONNumberNaN[M]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	var m M
+	return Dec128(n).marshalJSONNumberTo(enc, m.jsonNaN())
+}
+
+// UnmarshalJSONFrom implements the json.UnmarshalerFrom interface of encoding/json/v2. It accepts the same input as UnmarshalJSON.
+func (n *JSONNumberNaN[M]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	var m M
+	return (*Dec128)(n).unmarshalJSONFrom(dec, m.jsonNaN())
+}
+
+// marshalJSONNumberTo writes d as a bare JSON number, or as a JSON string if the json.StringifyNumbers option is set, with NaN encoded according to nan.
+func (d Dec128) marshalJSONNumberTo(enc *jsontext.Encoder, nan jsonNaN) error {
+	quote, _ := json.GetOption(enc.Options(), json.StringifyNumbers)
+	b, err := d.appendJSON(enc.AvailableBuffer(), quote, nan)
+	if err != nil {
+		return err
+	}
+	return enc.WriteValue(b)
+}
+
+// MarshalJSONTo implements the json.MarshalerTo interface of encoding/json/v2. An invalid value is written as null.
+func (n NullDec128) MarshalJSONTo(enc *jsontext.Encoder) error {
+	if !n.Valid {
+		return enc.WriteToken(jsontext.Null)
+	}
+	return n.Dec128.MarshalJSONTo(enc)
+}
+
+// UnmarshalJSONFrom implements the json.UnmarshalerFrom interface of encoding/json/v2. null is decoded as an invalid value.
+func (n *NullDec128) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	if dec.PeekKind() == 'n' {
+		if _, err := dec.ReadToken(); err != nil {
+			return err
+		}
+		n.Dec128, n.Valid = Zero, false
+		return nil
+	}
+
+	if err := n.Dec128.UnmarshalJSONFrom(dec); err != nil {
+		return err
+	}
+	n.Valid = true
+
+	return nil
+}
+
+// MarshalJSONTo implements the json.MarshalerTo interface of encoding/json/v2. It writes the same JSON string as MarshalJSON.
+func (f FixedDec128) MarshalJSONTo(enc *jsontext.Encoder) error {
+	b, err := f.appendJSON(enc.AvailableBuffer())
+	if err != nil {
+		return err
+	}
+	return enc.WriteValue(b)
+}
+
+// UnmarshalJSONFrom implements the json.UnmarshalerFrom interface of encoding/json/v2. It rescales the value to ColumnScale as Scan.
+func (f *FixedDec128) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	var d Dec128
+	if err := d.UnmarshalJSONFrom(dec); err != nil {
+		return err
+	}
+	return f.set(d)
+}
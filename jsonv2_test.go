@@ -0,0 +1,161 @@
+//go:build go1.27 && goexperiment.jsonv2
+
+package dec128
+
+import (
+	json "encoding/json/v2"
+	"fmt"
+	"testing"
+
+	"github.com/jokruger/dec128/state"
+)
+
+func TestJSONv2(t *testing.T) {
+	type doc struct {
+		S Dec128
+		N JSONNumber
+		Q JSONNumber `json:",string"`
+	}
+
+	type testCase struct {
+		i string
+		j string
+	}
+
+	testCases := [...]testCase{
+		{"0", `{"S":"0","N":0,"Q":"0"}`},
+		{"1.50", `{"S":"1.5","N":1.5,"Q":"1.5"}`},
+		{"-123.456", `{"S":"-123.456","N":-123.456,"Q":"-123.456"}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("TestJSONv2(%v)", tc.i), func(t *testing.T) {
+			d := FromString(tc.i)
+			b, err := json.Marshal(doc{S: d, N: JSONNumber(d), Q: JSONNumber(d)})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(b) != tc.j {
+				t.Errorf("expected %s, got: %s", tc.j, string(b))
+			}
+
+			var r doc
+			if err := json.Unmarshal(b, &r); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !r.S.Equal(d) || !Dec128(r.N).Equal(d) || !Dec128(r.Q).Equal(d) {
+				t.Errorf("expected %s, got: %s, %s and %s", d.String(), r.S.String(), r.N.String(), r.Q.String())
+			}
+		})
+	}
+
+	b, err := json.Marshal(struct{ N JSONNumber }{JSONNumber(FromString("2.5"))}, json.StringifyNumbers(true))
+	if err != nil || string(b) != `{"N":"2.5"}` {
+		t.Errorf("expected {\"N\":\"2.5\"}, got: %s (%v)", string(b), err)
+	}
+
+	var r doc
+	if err := json.Unmarshal([]byte(`{"S":1.25,"N":"2.5","Q":null}`), &r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.S.String() != "1.25" || r.N.String() != "2.5" || r.Q.String() != "0" {
+		t.Errorf("unexpected values: %s %s %s", r.S.String(), r.N.String(), r.Q.String())
+	}
+
+	for _, s := range []string{`{"S":true}`, `{"N":[1]}`, `{"S":"1x"}`} {
+		if err := json.Unmarshal([]byte(s), &r); err == nil {
+			t.Errorf("%s: expected error", s)
+		}
+	}
+
+	if err := json.Unmarshal([]byte(`{"S":"NaN"}`), &r); err == nil {
+		t.Errorf("expected error for NaN, got: %s", r.S.String())
+	}
+
+	type nanDoc struct {
+		S JSONString[JSONNaNNull]
+		N JSONNumberNaN[JSONNaNString]
+		E JSONNumberNaN[JSONNaNError]
+	}
+
+	nan := NaN(state.NaN)
+	b, err = json.Marshal(nanDoc{S: JSONString[JSONNaNNull](nan), N: JSONNumberNaN[JSONNaNString](nan)})
+	if err != nil || string(b) != `{"S":null,"N":"NaN","E":0}` {
+		t.Errorf("unexpected result: %s (%v)", string(b), err)
+	}
+	var n nanDoc
+	if err := json.Unmarshal(b, &n); err != nil || !Dec128(n.S).IsNaN() || !Dec128(n.N).IsNaN() {
+		t.Errorf("expected NaN, got: %s %s (%v)", n.S.String(), n.N.String(), err)
+	}
+	if b, err := json.Marshal(nanDoc{N: JSONNumberNaN[JSONNaNString](FromString("1.5"))}, json.StringifyNumbers(true)); err != nil || string(b) != `{"S":"0","N":"1.5","E":"0"}` {
+		t.Errorf("unexpected result: %s (%v)", string(b), err)
+	}
+	if _, err := json.Marshal(nanDoc{E: JSONNumberNaN[JSONNaNError](nan)}); err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+func BenchmarkDec128MarshalJSONv2(b *testing.B) {
+	v := testJsonStruct{A: FromString("123.456"), B: FromString("-0.001"), C: FromString("1234567890.123456789")}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = json.Marshal(v)
+	}
+}
+
+func TestNullDec128JSONv2(t *testing.T) {
+	type doc struct {
+		A NullDec128
+		B NullDec128
+	}
+
+	b, err := json.Marshal(doc{A: NewNullDec128(FromString("1.5"))})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(b) != `{"A":"1.5","B":null}` {
+		t.Errorf("expected %s, got: %s", `{"A":"1.5","B":null}`, string(b))
+	}
+
+	r := doc{B: NewNullDec128(Decimal1)}
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !r.A.Valid || r.A.String() != "1.5" || r.B.Valid {
+		t.Errorf("unexpected values: %v %s, %v %s", r.A.Valid, r.A.String(), r.B.Valid, r.B.String())
+	}
+}
+
+func TestFixedDec128JSONv2(t *testing.T) {
+	type doc struct {
+		A FixedDec128
+	}
+
+	b, err := json.Marshal(doc{A: Fixed(FromString("1.5"), 2)})
+	if err != nil || string(b) != `{"A":"1.50"}` {
+		t.Errorf("expected %s, got: %s (%v)", `{"A":"1.50"}`, string(b), err)
+	}
+
+	r := doc{A: Fixed(Zero, 3)}
+	if err := json.Unmarshal([]byte(`{"A":1.5}`), &r); err != nil || r.A.StringFixed() != "1.500" {
+		t.Errorf("expected 1.500, got: %s (%v)", r.A.StringFixed(), err)
+	}
+	if err := json.Unmarshal([]byte(`{"A":"1.5555"}`), &r); err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+func TestJSONv2NumberFormat(t *testing.T) {
+	// the format is chosen per field with JSONNumber and the string tag option
+	type alt struct {
+		N JSONNumber
+		Q JSONNumber `json:",string"`
+	}
+	d := JSONNumber(FromString("1.5"))
+	if b, err := json.Marshal(alt{N: d, Q: d}); err != nil || string(b) != `{"N":1.5,"Q":"1.5"}` {
+		t.Errorf("expected %s, got: %s (%v)", `{"N":1.5,"Q":"1.5"}`, string(b), err)
+	}
+	if b, err := json.Marshal(alt{N: d}, json.StringifyNumbers(true)); err != nil || string(b) != `{"N":"1.5","Q":"0"}` {
+		t.Errorf("expected %s, got: %s (%v)", `{"N":"1.5","Q":"0"}`, string(b), err)
+	}
+}
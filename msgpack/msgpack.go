@@ -0,0 +1,110 @@
+// Package msgpack encodes Dec128 as a MessagePack extension type without depending on a MessagePack library.
+//
+// The extension payload is the Dec128 binary layout produced by EncodeBinary (1 to 18 bytes),
+// wrapped in the smallest fixext or ext 8 header.
+package msgpack
+
+import (
+	"io"
+
+	"github.com/jokruger/dec128"
+	"github.com/jokruger/dec128/state"
+)
+
+// DefaultExtType is the application-specific extension type used by the package level functions.
+const DefaultExtType = int8(1)
+
+// MaxBytes is the maximum number of bytes of an encoded Dec128: ext 8 header (3 bytes) and the binary layout.
+const MaxBytes = 3 + dec128.MaxBytes
+
+// MessagePack format bytes
+const (
+	fixext1  = 0xd4
+	fixext2  = 0xd5
+	fixext4  = 0xd6
+	fixext8  = 0xd7
+	fixext16 = 0xd8
+	ext8     = 0xc7
+)
+
+// AppendExt appends d as a MessagePack extension of DefaultExtType to buf and returns the extended buffer.
+// It does not allocate if buf has enough capacity.
+func AppendExt(buf []byte, d dec128.Dec128) ([]byte, error) {
+	return AppendExtType(buf, DefaultExtType, d)
+}
+
+// AppendExtType appends d as a MessagePack extension of type typ to buf and returns the extended buffer.
+// It does not allocate if buf has enough capacity.
+func AppendExtType(buf []byte, typ int8, d dec128.Dec128) ([]byte, error) {
+	var tmp [dec128.MaxBytes]byte
+	n, err := d.EncodeBinary(tmp[:])
+	if err != nil {
+		return buf, err
+	}
+
+	switch n {
+	case 1:
+		buf = append(buf, fixext1, byte(typ))
+	case 2:
+		buf = append(buf, fixext2, byte(typ))
+	default:
+		buf = append(buf, ext8, byte(n), byte(typ))
+	}
+
+	return append(buf, tmp[:n]...), nil
+}
+
+// DecodeExt decodes a MessagePack extension of DefaultExtType from the beginning of b.
+// It returns the Dec128 and the number of bytes consumed.
+func DecodeExt(b []byte) (dec128.Dec128, int, error) {
+	return DecodeExtType(b, DefaultExtType)
+}
+
+// DecodeExtType decodes a MessagePack extension of type typ from the beginning of b.
+// It returns the Dec128 and the number of bytes consumed.
+// It returns an error if b does not start with an extension of type typ or the payload is not a valid binary layout.
+func DecodeExtType(b []byte, typ int8) (dec128.Dec128, int, error) {
+	if len(b) == 0 {
+		return dec128.Zero, 0, io.ErrShortBuffer
+	}
+
+	var n, pos int
+	switch b[0] {
+	case fixext1:
+		n, pos = 1, 1
+	case fixext2:
+		n, pos = 2, 1
+	case fixext4:
+		n, pos = 4, 1
+	case fixext8:
+		n, pos = 8, 1
+	case fixext16:
+		n, pos = 16, 1
+	case ext8:
+		if len(b) < 2 {
+			return dec128.Zero, 0, io.ErrShortBuffer
+		}
+		n, pos = int(b[1]), 2
+	default:
+		return dec128.Zero, 0, state.InvalidFormat.Error()
+	}
+
+	switch {
+	case len(b) < pos+1+n:
+		return dec128.Zero, 0, io.ErrShortBuffer
+	case int8(b[pos]) != typ:
+		return dec128.Zero, 0, state.InvalidFormat.Error()
+	}
+	pos++
+
+	var d dec128.Dec128
+	sz, err := d.DecodeBinary(b[pos : pos+n])
+	switch {
+	case err != nil:
+		return dec128.Zero, 0, state.InvalidFormat.Error()
+	case sz != n:
+		return dec128.Zero, 0, state.InvalidFormat.Error()
+	}
+
+	return d, pos + n, nil
+}
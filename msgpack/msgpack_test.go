@@ -0,0 +1,89 @@
+package msgpack
+
+import (
+	"encoding/hex"
+	"io"
+	"testing"
+
+	"github.com/jokruger/dec128"
+	"github.com/jokruger/dec128/state"
+)
+
+func TestExt(t *testing.T) {
+	type testCase struct {
+		i string
+		b string
+	}
+
+	testCases := [...]testCase{
+		{"0", "d40100"},
+		{"0.00", "d40100"},
+		{"1", "c70901400100000000000000"},
+		{"-1.5", "c70a01610f0000000000000001"},
+		{"18446744073709551616", "c70901800100000000000000"},
+		{"NaN", "d40109"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.i, func(t *testing.T) {
+			d := dec128.FromString(tc.i)
+
+			buf, err := AppendExt(nil, d)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s := hex.EncodeToString(buf); s != tc.b {
+				t.Errorf("expected %s, got %s", tc.b, s)
+			}
+
+			r, n, err := DecodeExt(append(buf, 0xc0))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if n != len(buf) {
+				t.Errorf("expected %d bytes consumed, got %d", len(buf), n)
+			}
+			if r.String() != d.String() {
+				t.Errorf("expected %s, got %s", d.String(), r.String())
+			}
+		})
+	}
+
+	buf := make([]byte, 0, MaxBytes)
+	d := dec128.FromString("-340282366920938463463374607431768211.455")
+	if n := testing.AllocsPerRun(100, func() { buf, _ = AppendExtType(buf[:0], 42, d) }); n != 0 {
+		t.Errorf("expected no allocations, got %v", n)
+	}
+	if len(buf) != MaxBytes {
+		t.Errorf("expected %d bytes, got %d", MaxBytes, len(buf))
+	}
+	if r, _, err := DecodeExtType(buf, 42); err != nil || !r.Equal(d) {
+		t.Errorf("expected %s, got %s (%v)", d.String(), r.String(), err)
+	}
+}
+
+func TestDecodeExtErrors(t *testing.T) {
+	type testCase struct {
+		b string
+		e error
+	}
+
+	testCases := [...]testCase{
+		{"", io.ErrShortBuffer},
+		{"c7", io.ErrShortBuffer},
+		{"c70901400100", io.ErrShortBuffer},
+		{"d402", io.ErrShortBuffer},
+		{"d40200", state.InvalidFormat.Error()},   // other extension type
+		{"c0", state.InvalidFormat.Error()},       // nil
+		{"d50140aa", state.InvalidFormat.Error()}, // payload shorter than announced by flags
+		{"d5010000", state.InvalidFormat.Error()}, // trailing payload bytes
+		{"d6014001000000", state.InvalidFormat.Error()},
+	}
+
+	for _, tc := range testCases {
+		b, _ := hex.DecodeString(tc.b)
+		if d, _, err := DecodeExt(b); err != tc.e {
+			t.Errorf("%s: expected error %v, got %v (%s)", tc.b, tc.e, err, d.String())
+		}
+	}
+}
@@ -0,0 +1,222 @@
+// Package mysqldecimal converts Dec128 to and from the packed binary DECIMAL(M,D) storage format of MySQL and MariaDB (as found in row-based binlog events).
+//
+// The integer and fractional digits are stored separately in groups of nine digits per four bytes,
+// with a shorter leading integer group and trailing fractional group. Negative values have all bits inverted,
+// and the highest bit of the first byte is flipped for all values.
+package mysqldecimal
+
+import (
+	"encoding/binary"
+
+	"github.com/jokruger/dec128"
+	"github.com/jokruger/dec128/state"
+	"github.com/jokruger/dec128/uint128"
+)
+
+const (
+	// MaxPrecision is the maximum number of digits of a DECIMAL column.
+	MaxPrecision = 65
+
+	// MaxScale is the maximum number of digits after the decimal point of a DECIMAL column.
+	MaxScale = 30
+
+	digitsPerGroup = 9
+	bytesPerGroup  = 4
+)
+
+// number of bytes used to store the given number of leftover digits
+var dig2bytes = [digitsPerGroup + 1]int{0, 1, 1, 2, 2, 3, 3, 4, 4, 4}
+
+// Size returns the number of bytes used to store a DECIMAL(precision, scale) value, or -1 if precision and scale are invalid.
+func Size(precision int, scale int) int {
+	if !valid(precision, scale) {
+		return -1
+	}
+	intg := precision - scale
+	return intg/digitsPerGroup*bytesPerGroup + dig2bytes[intg%digitsPerGroup] + scale/digitsPerGroup*bytesPerGroup + dig2bytes[scale%digitsPerGroup]
+}
+
+// Encode returns d in the DECIMAL(precision, scale) binary format.
+// It returns an error if d is NaN, has more digits after the decimal point than scale, or does not fit into precision.
+func Encode(d dec128.Dec128, precision int, scale int) ([]byte, error) {
+	return Append(make([]byte, 0, max(Size(precision, scale), 0)), d, precision, scale)
+}
+
+// Append appends d in the DECIMAL(precision, scale) binary format to buf and returns the extended buffer.
+// It returns an error if d is NaN, has more digits after the decimal point than scale, or does not fit into precision.
+func Append(buf []byte, d dec128.Dec128, precision int, scale int) ([]byte, error) {
+	if !valid(precision, scale) {
+		return buf, state.InvalidFormat.Error()
+	}
+	if d.IsNaN() {
+		return buf, d.ErrorDetails()
+	}
+
+	intg := precision - scale
+	ds := int(d.Scale())
+
+	// split coefficient into integer and fractional digits
+	ipart, f, _ := d.Coefficient().QuoRem64(dec128.Pow10Uint64[ds])
+	var tmp [uint128.MaxStrLen]byte
+	idigits := ipart.StringToBuf(tmp[:])
+	if len(idigits) > intg {
+		return buf, state.Overflow.Error()
+	}
+
+	// fractional digits, left aligned and padded with zeros to scale
+	var fdigits [MaxScale]byte
+	for i := range fdigits {
+		fdigits[i] = '0'
+	}
+	for i := ds - 1; i >= 0; i-- {
+		c := '0' + byte(f%10)
+		f /= 10
+		if i >= scale {
+			if c != '0' {
+				return buf, state.RescaleToLowerScale.Error()
+			}
+			continue
+		}
+		fdigits[i] = c
+	}
+
+	var mask byte
+	if d.IsNegative() {
+		mask = 0xff
+	}
+
+	start := len(buf)
+
+	// integer groups, the leading group holds the leftover digits
+	pad := intg - len(idigits)
+	var v uint32
+	for i := range intg {
+		if i >= pad {
+			v = v*10 + uint32(idigits[i-pad]-'0')
+		} else {
+			v *= 10
+		}
+		if n := intg - i - 1; n%digitsPerGroup == 0 {
+			k := digitsPerGroup
+			if i < intg%digitsPerGroup {
+				k = intg % digitsPerGroup
+			}
+			buf = appendGroup(buf, v, dig2bytes[k], mask)
+			v = 0
+		}
+	}
+
+	// fractional groups, the trailing group holds the leftover digits
+	for i := range scale {
+		v = v*10 + uint32(fdigits[i]-'0')
+		if (i+1)%digitsPerGroup == 0 || i == scale-1 {
+			buf = appendGroup(buf, v, dig2bytes[i%digitsPerGroup+1], mask)
+			v = 0
+		}
+	}
+
+	buf[start] ^= 0x80
+
+	return buf, nil
+}
+
+// Decode creates a new Dec128 from the DECIMAL(precision, scale) binary format in b.
+// The scale of the result is the column scale, reduced by trailing zeros if the coefficient would not fit into 128 bits otherwise.
+// Columns with a scale above dec128.MaxScale are accepted if the extra digits are zeros.
+// Additional bytes after the value are ignored.
+// In case of errors, it returns NaN with the corresponding error.
+func Decode(b []byte, precision int, scale int) dec128.Dec128 {
+	sz := Size(precision, scale)
+	switch {
+	case sz < 0:
+		return dec128.NaN(state.InvalidFormat)
+	case len(b) < sz:
+		return dec128.NaN(state.NotEnoughBytes)
+	}
+
+	var mask byte
+	if b[0]&0x80 == 0 {
+		mask = 0xff
+	}
+
+	intg := precision - scale
+	rscale := min(scale, int(dec128.MaxScale))
+
+	var ipart uint128.Uint128
+	var frac uint64
+	var s state.State
+	pos := 0
+
+	// read reads a group of k digits and keeps only its first n digits, the dropped digits must be zeros
+	read := func(k int, n int) (uint64, state.State) {
+		nb := dig2bytes[k]
+		var v uint64
+		for i := range nb {
+			c := b[pos+i] ^ mask
+			if pos+i == 0 {
+				c ^= 0x80
+			}
+			v = v<<8 | uint64(c)
+		}
+		pos += nb
+		if v >= dec128.Pow10Uint64[k] {
+			return 0, state.InvalidFormat
+		}
+		p := dec128.Pow10Uint64[k-n]
+		if v%p != 0 {
+			return 0, state.ScaleOutOfRange
+		}
+		return v / p, state.OK
+	}
+
+	for i := 0; i < intg; {
+		k := intg % digitsPerGroup
+		if i > 0 || k == 0 {
+			k = digitsPerGroup
+		}
+		v, st := read(k, k)
+		if st >= state.Error {
+			return dec128.NaN(st)
+		}
+		if ipart, s = ipart.MulAdd64(dec128.Pow10Uint64[k], v); s >= state.Error {
+			return dec128.NaN(s)
+		}
+		i += k
+	}
+
+	for i := 0; i < scale; i += digitsPerGroup {
+		k := min(scale-i, digitsPerGroup)
+		n := min(max(rscale-i, 0), k)
+		v, st := read(k, n)
+		if st >= state.Error {
+			return dec128.NaN(st)
+		}
+		frac = frac*dec128.Pow10Uint64[n] + v
+	}
+
+	// use the column scale, or fewer digits if the coefficient would overflow otherwise
+	for {
+		coef, st := ipart.MulAdd64(dec128.Pow10Uint64[rscale], frac)
+		if st < state.Error {
+			return dec128.New(coef, uint8(rscale), mask == 0xff)
+		}
+		if rscale == 0 || frac%10 != 0 {
+			return dec128.NaN(st)
+		}
+		frac /= 10
+		rscale--
+	}
+}
+
+func valid(precision int, scale int) bool {
+	return precision > 0 && precision <= MaxPrecision && scale >= 0 && scale <= MaxScale && scale <= precision
+}
+
+func appendGroup(buf []byte, v uint32, n int, mask byte) []byte {
+	var tmp [bytesPerGroup]byte
+	binary.BigEndian.PutUint32(tmp[:], v)
+	for _, c := range tmp[bytesPerGroup-n:] {
+		buf = append(buf, c^mask)
+	}
+	return buf
+}
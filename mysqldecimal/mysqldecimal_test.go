@@ -0,0 +1,129 @@
+package mysqldecimal
+
+import (
+	"encoding/hex"
+	"math/rand"
+	"testing"
+
+	"github.com/jokruger/dec128"
+	"github.com/jokruger/dec128/state"
+	"github.com/jokruger/dec128/uint128"
+)
+
+func TestMySQLDecimal(t *testing.T) {
+	type testCase struct {
+		i string
+		m int
+		d int
+		b string
+	}
+
+	testCases := [...]testCase{
+		{"1234567890.1234", 14, 4, "810dfb38d204d2"},
+		{"-1234567890.1234", 14, 4, "7ef204c72dfb2d"},
+		{"0", 10, 2, "8000000000"},
+		{"1.5", 10, 2, "8000000132"},
+		{"-1.5", 10, 2, "7ffffffecd"},
+		{"99999999.99", 10, 2, "85f5e0ff63"},
+		{"123456789012345678901234567890.123456789", 65, 30, "8000007b1b3a0c14149aa4350dfb38d2075bcd1500000000000000000000"},
+		{"-0.0000000000000000001", 30, 30, "7ffffffffffffffffa0a1effffff"},
+		{"12345", 5, 0, "803039"},
+		{"0.123456789", 9, 9, "875bcd15"},
+		{"-340282366920938463463374607431768211455", 39, 0, "7eabef2b6c37c8102f18e9abf1b8d2360600"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.i, func(t *testing.T) {
+			d := dec128.FromString(tc.i)
+			b, err := Encode(d, tc.m, tc.d)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s := hex.EncodeToString(b); s != tc.b {
+				t.Errorf("expected %s, got %s", tc.b, s)
+			}
+			if len(b) != Size(tc.m, tc.d) {
+				t.Errorf("expected size %d, got %d", Size(tc.m, tc.d), len(b))
+			}
+			r := Decode(b, tc.m, tc.d)
+			if r.IsNaN() {
+				t.Fatalf("unexpected error: %v", r.ErrorDetails())
+			}
+			if !r.Equal(d) || r.Scale() < d.Scale() {
+				t.Errorf("expected %s, got %s", d.String(), r.StringFixed())
+			}
+		})
+	}
+}
+
+func TestMySQLDecimalErrors(t *testing.T) {
+	type testCase struct {
+		i string
+		m int
+		d int
+	}
+
+	for _, tc := range [...]testCase{
+		{"NaN", 10, 2},
+		{"1", 0, 0},
+		{"1", 66, 0},
+		{"1", 40, 31},
+		{"1", 5, 6},
+		{"100000000", 10, 2},
+		{"1.005", 10, 2},
+	} {
+		if _, err := Encode(dec128.FromString(tc.i), tc.m, tc.d); err == nil {
+			t.Errorf("%v: expected error, got nil", tc)
+		}
+	}
+
+	type decodeCase struct {
+		b string
+		m int
+		d int
+		s string
+		e state.State
+	}
+
+	for _, tc := range [...]decodeCase{
+		{"8000000132", 66, 2, "", state.InvalidFormat},
+		{"80000001", 10, 2, "", state.NotEnoughBytes},
+		{"80000001ff", 10, 2, "", state.InvalidFormat},
+		{"800000000000000005f5e1000000", 30, 30, "0.0000000000000000001", state.OK},
+		{"8000000000000000009896800000", 30, 30, "", state.ScaleOutOfRange},
+		{"8000000000000000000000271000000000000000000000000000000000", 65, 0, "", state.Overflow},
+		{"7fffffffffffffffffffffff9bffffffffffffffffffffffffffffffff", 65, 0, "-100000000000000000000000000000000000000", state.OK},
+		{"800000013200", 10, 2, "1.50", state.OK},
+	} {
+		b, _ := hex.DecodeString(tc.b)
+		d := Decode(b, tc.m, tc.d)
+		switch {
+		case tc.e != state.OK:
+			if d.ErrorDetails() != tc.e.Error() {
+				t.Errorf("%s: expected error %v, got %s (%v)", tc.b, tc.e.Error(), d.StringFixed(), d.ErrorDetails())
+			}
+		case d.IsNaN():
+			t.Errorf("%s: unexpected error %v", tc.b, d.ErrorDetails())
+		case d.StringFixed() != tc.s:
+			t.Errorf("%s: expected %s, got %s", tc.b, tc.s, d.StringFixed())
+		}
+	}
+}
+
+func TestMySQLDecimalRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(12345))
+	for range 10000 {
+		d := dec128.New(uint128.Uint128{Lo: r.Uint64(), Hi: r.Uint64() >> uint(r.Intn(64))}, uint8(r.Intn(int(dec128.MaxScale+1))), r.Intn(2) == 1)
+		intg := len(d.Abs().Trunc(0).String())
+		scale := int(d.Scale()) + r.Intn(min(MaxScale, MaxPrecision-intg)-int(d.Scale())+1)
+		precision := intg + scale
+		b, err := Encode(d, precision, scale)
+		if err != nil {
+			t.Fatalf("unexpected error for %s (%d, %d): %v", d.String(), precision, scale, err)
+		}
+		x := Decode(b, precision, scale)
+		if !x.Equal(d) {
+			t.Fatalf("round trip failed for (%d, %d): %s != %s", precision, scale, x.StringFixed(), d.StringFixed())
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package dec128
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"io"
+
+	"github.com/jokruger/dec128/state"
+)
+
+// NullDec128 represents a Dec128 that may be null, like sql.NullFloat64.
+// It implements the sql.Scanner and driver.Valuer interfaces, and encodes an invalid value as SQL NULL, JSON null and empty text.
+// Its binary and gob forms carry the validity; the other binary methods (e.g. EncodeBinary and WriteBinary) are those of Dec128 and ignore Valid.
+type NullDec128 struct {
+	Dec128
+	Valid bool // Valid is true if Dec128 is not NULL
+}
+
+// NewNullDec128 returns a valid NullDec128 holding d.
+func NewNullDec128(d Dec128) NullDec128 {
+	return NullDec128{Dec128: d, Valid: true}
+}
+
+// Scan implements the sql.Scanner interface. NULL sets Valid to false and Dec128 to Zero.
+func (n *NullDec128) Scan(src any) error {
+	if src == nil {
+		n.Dec128, n.Valid = Zero, false
+		return nil
+	}
+
+	if err := n.Dec128.Scan(src); err != nil {
+		n.Valid = false
+		return err
+	}
+	n.Valid = true
+
+	return nil
+}
+
+// Value implements the driver.Valuer interface. An invalid value is NULL.
+func (n NullDec128) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Dec128.Value()
+}
+
+// MarshalJSON implements the json.Marshaler interface. An invalid value is encoded as null.
+func (n NullDec128) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return nullValue, nil
+	}
+	return n.Dec128.MarshalJSON()
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. null is decoded as an invalid value, anything else as Dec128.UnmarshalJSON does.
+func (n *NullDec128) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, nullValue) {
+		n.Dec128, n.Valid = Zero, false
+		return nil
+	}
+
+	if err := n.Dec128.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	n.Valid = true
+
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface. An invalid value is encoded as empty text.
+func (n NullDec128) MarshalText() ([]byte, error) {
+	if !n.Valid {
+		return []byte{}, nil
+	}
+	return n.Dec128.MarshalText()
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface. Empty text is decoded as an invalid value.
+func (n *NullDec128) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		n.Dec128, n.Valid = Zero, false
+		return nil
+	}
+
+	if err := n.Dec128.UnmarshalText(data); err != nil {
+		return err
+	}
+	n.Valid = true
+
+	return nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// The binary form is a validity byte (0 for an invalid value, 1 for a valid one), followed by the binary form of Dec128 for a valid value.
+func (n NullDec128) MarshalBinary() ([]byte, error) {
+	return n.AppendBinary(make([]byte, 0, MaxBytes+1))
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface. It accepts the binary form of MarshalBinary.
+func (n *NullDec128) UnmarshalBinary(data []byte) error {
+	switch {
+	case len(data) == 0:
+		return io.ErrShortBuffer
+	case data[0] == 0 && len(data) == 1:
+		n.Dec128, n.Valid = Zero, false
+		return nil
+	case data[0] != 1:
+		return state.InvalidFormat.Error()
+	}
+
+	var d Dec128
+	if err := d.UnmarshalBinary(data[1:]); err != nil {
+		return err
+	}
+	n.Dec128, n.Valid = d, true
+
+	return nil
+}
+
+// AppendBinary appends the binary form of MarshalBinary to buf and returns the extended buffer.
+func (n NullDec128) AppendBinary(buf []byte) ([]byte, error) {
+	if !n.Valid {
+		return append(buf, 0), nil
+	}
+	b, err := n.Dec128.AppendBinary(append(buf, 1))
+	if err != nil {
+		return buf, err
+	}
+	return b, nil
+}
+
+// GobEncode implements the gob.GobEncoder interface for gob serialization. It uses the binary form of MarshalBinary.
+func (n NullDec128) GobEncode() ([]byte, error) {
+	return n.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface for gob serialization.
+func (n *NullDec128) GobDecode(data []byte) error {
+	return n.UnmarshalBinary(data)
+}
+
+// String returns the string representation of the value, or "NULL" if it is invalid.
+func (n NullDec128) String() string {
+	if !n.Valid {
+		return "NULL"
+	}
+	return n.Dec128.String()
+}
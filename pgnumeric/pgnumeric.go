@@ -0,0 +1,185 @@
+// Package pgnumeric converts Dec128 to and from the PostgreSQL NUMERIC binary wire format.
+//
+// The format is a header of four big-endian 16-bit words (number of digits, weight, sign and display scale)
+// followed by the base-10000 digits, most significant first. The value is the sum of digit[i] * 10000^(weight-i).
+package pgnumeric
+
+import (
+	"encoding/binary"
+
+	"github.com/jokruger/dec128"
+	"github.com/jokruger/dec128/state"
+	"github.com/jokruger/dec128/uint128"
+)
+
+// OID is the PostgreSQL type OID of NUMERIC.
+const OID = 1700
+
+// Sign values of the NUMERIC binary format.
+const (
+	SignPositive = uint16(0x0000)
+	SignNegative = uint16(0x4000)
+	SignNaN      = uint16(0xc000)
+	SignPosInf   = uint16(0xd000)
+	SignNegInf   = uint16(0xf000)
+)
+
+// MaxBytes is the maximum number of bytes of an encoded Dec128: header plus 10 integer and 5 fractional base-10000 digits.
+const MaxBytes = 8 + 2*15
+
+const nbase = 10000
+
+// EncodePGNumeric returns the NUMERIC binary representation of d.
+// NaN is encoded as the NUMERIC NaN value.
+func EncodePGNumeric(d dec128.Dec128) []byte {
+	return AppendPGNumeric(make([]byte, 0, MaxBytes), d)
+}
+
+// AppendPGNumeric appends the NUMERIC binary representation of d to buf and returns the extended buffer.
+// NaN is encoded as the NUMERIC NaN value.
+func AppendPGNumeric(buf []byte, d dec128.Dec128) []byte {
+	if d.IsNaN() {
+		return appendHeader(buf, 0, 0, SignNaN, 0)
+	}
+
+	scale := d.Scale()
+
+	// pad the fractional part to a whole number of base-10000 digits
+	ipart, f, _ := d.Coefficient().QuoRem64(dec128.Pow10Uint64[scale])
+	nfrac := (int(scale) + 3) / 4
+	frac, _ := uint128.FromUint64(f).Mul64(dec128.Pow10Uint64[nfrac*4-int(scale)])
+
+	// collect digits least significant first
+	var digits [MaxBytes / 2]uint16
+	n := 0
+	for range nfrac {
+		var r uint64
+		frac, r, _ = frac.QuoRem64(nbase)
+		digits[n] = uint16(r)
+		n++
+	}
+	for !ipart.IsZero() {
+		var r uint64
+		ipart, r, _ = ipart.QuoRem64(nbase)
+		digits[n] = uint16(r)
+		n++
+	}
+
+	// strip trailing (least significant) and leading (most significant) zero digits
+	lo := 0
+	for lo < n && digits[lo] == 0 {
+		lo++
+	}
+	for n > lo && digits[n-1] == 0 {
+		n--
+	}
+
+	if lo == n {
+		return appendHeader(buf, 0, 0, SignPositive, uint16(scale))
+	}
+
+	sign := SignPositive
+	if d.IsNegative() {
+		sign = SignNegative
+	}
+
+	buf = appendHeader(buf, uint16(n-lo), uint16(int16(n-nfrac-1)), sign, uint16(scale))
+	for i := n - 1; i >= lo; i-- {
+		buf = binary.BigEndian.AppendUint16(buf, digits[i])
+	}
+
+	return buf
+}
+
+// DecodePGNumeric creates a new Dec128 from the NUMERIC binary representation in b.
+// The scale of the result is the display scale of the value.
+// NaN is decoded as NaN and infinities as NaN with overflow error.
+// Values with a display scale above dec128.MaxScale are accepted if the extra digits are zeros.
+// In case of errors, it returns NaN with the corresponding error.
+func DecodePGNumeric(b []byte) dec128.Dec128 {
+	if len(b) < 8 {
+		return dec128.NaN(state.NotEnoughBytes)
+	}
+
+	ndigits := int(binary.BigEndian.Uint16(b[0:]))
+	weight := int(int16(binary.BigEndian.Uint16(b[2:])))
+	sign := binary.BigEndian.Uint16(b[4:])
+	dscale := int(binary.BigEndian.Uint16(b[6:]))
+
+	switch sign {
+	case SignPositive, SignNegative:
+	case SignNaN:
+		return dec128.NaN(state.NaN)
+	case SignPosInf, SignNegInf:
+		return dec128.NaN(state.Overflow)
+	default:
+		return dec128.NaN(state.InvalidFormat)
+	}
+
+	switch {
+	case len(b) < 8+2*ndigits:
+		return dec128.NaN(state.NotEnoughBytes)
+	case len(b) > 8+2*ndigits || dscale > 0x3fff:
+		return dec128.NaN(state.InvalidFormat)
+	}
+
+	digits := b[8:]
+
+	// ignore trailing zero digits
+	for ndigits > 0 && binary.BigEndian.Uint16(digits[2*(ndigits-1):]) == 0 {
+		ndigits--
+	}
+
+	if ndigits == 0 {
+		return dec128.New(uint128.Zero, uint8(min(dscale, int(dec128.MaxScale))), false)
+	}
+
+	// the value is digits * 10^exp, the last digit is shortened by k decimal places so that no digits are below the scale
+	scale := min(dscale, int(dec128.MaxScale))
+	exp := 4 * (weight - ndigits + 1)
+	k := max(-(exp + scale), 0)
+
+	var coef uint128.Uint128
+	var s state.State
+	for i := range ndigits {
+		dg := uint64(binary.BigEndian.Uint16(digits[2*i:]))
+		if dg >= nbase {
+			return dec128.NaN(state.InvalidFormat)
+		}
+		mul := uint64(nbase)
+		if i == ndigits-1 && k > 0 {
+			if k >= 4 || dg%dec128.Pow10Uint64[k] != 0 {
+				if dscale > int(dec128.MaxScale) {
+					return dec128.NaN(state.ScaleOutOfRange)
+				}
+				return dec128.NaN(state.InvalidFormat)
+			}
+			mul = dec128.Pow10Uint64[4-k]
+			dg /= dec128.Pow10Uint64[k]
+			exp += k
+		}
+		coef, s = coef.MulAdd64(mul, dg)
+		if s >= state.Error {
+			return dec128.NaN(s)
+		}
+	}
+
+	if p := exp + scale; p > 0 {
+		if p >= len(dec128.Pow10Uint128) {
+			return dec128.NaN(state.Overflow)
+		}
+		coef, s = coef.Mul(dec128.Pow10Uint128[p])
+		if s >= state.Error {
+			return dec128.NaN(s)
+		}
+	}
+
+	return dec128.New(coef, uint8(scale), sign == SignNegative)
+}
+
+func appendHeader(buf []byte, ndigits uint16, weight uint16, sign uint16, dscale uint16) []byte {
+	buf = binary.BigEndian.AppendUint16(buf, ndigits)
+	buf = binary.BigEndian.AppendUint16(buf, weight)
+	buf = binary.BigEndian.AppendUint16(buf, sign)
+	return binary.BigEndian.AppendUint16(buf, dscale)
+}
@@ -0,0 +1,109 @@
+package pgnumeric
+
+import (
+	"encoding/hex"
+	"math/rand"
+	"testing"
+
+	"github.com/jokruger/dec128"
+	"github.com/jokruger/dec128/state"
+	"github.com/jokruger/dec128/uint128"
+)
+
+func TestPGNumeric(t *testing.T) {
+	type testCase struct {
+		i string
+		b string
+	}
+
+	testCases := [...]testCase{
+		{"0", "0000000000000000"},
+		{"0.00", "0000000000000002"},
+		{"1", "00010000000000000001"},
+		{"-1", "00010000400000000001"},
+		{"10000", "00010001000000000001"},
+		{"12345", "000200010000000000010929"},
+		{"1.5", "000200000000000100011388"},
+		{"123.45", "0002000000000002007b1194"},
+		{"-0.0001", "0001ffff400000040001"},
+		{"0.00001", "0001fffe0000000503e8"},
+		{"1.0000", "00010000000000040001"},
+		{"12345678901234567890.123456789", "000800040000000904d2162e23340d801ed204d2162e2328"},
+		{"0.0000000000000000001", "0001fffb00000013000a"},
+		{"340282366920938463463374607431768211455", "000a00090000000001540b071a2403aa121a18c111ff10dd1aa505af"},
+		{"-34028236692093846346.3374607431768211455", "000a0004400000130d4a202c1b0824a818ca0d2e17ba0c68201311c6"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.i, func(t *testing.T) {
+			d := dec128.FromString(tc.i)
+			b := EncodePGNumeric(d)
+			if s := hex.EncodeToString(b); s != tc.b {
+				t.Errorf("expected %s, got %s", tc.b, s)
+			}
+			r := DecodePGNumeric(b)
+			if r.IsNaN() {
+				t.Fatalf("unexpected error: %v", r.ErrorDetails())
+			}
+			if !r.Equal(d) || r.Scale() != d.Scale() {
+				t.Errorf("expected %s, got %s", d.StringFixed(), r.StringFixed())
+			}
+		})
+	}
+}
+
+func TestPGNumericDecode(t *testing.T) {
+	type testCase struct {
+		b string
+		s string
+		e state.State
+	}
+
+	testCases := [...]testCase{
+		{"0000000000000000", "0", state.OK},
+		{"00000000c0000000", "", state.NaN},
+		{"00000000d0000000", "", state.Overflow},
+		{"00000000f0000000", "", state.Overflow},
+		{"0000000080000000", "", state.InvalidFormat},
+		{"000000000000", "", state.NotEnoughBytes},
+		{"00010000000000000001" + "0001", "", state.InvalidFormat},
+		{"0002000000000000" + "0001", "", state.NotEnoughBytes},
+		{"00010000000000002710", "", state.InvalidFormat},
+		{"000200000000000000010000", "1", state.OK},                      // trailing zero digit
+		{"0001ffff00000019" + "0001", "0.0001000000000000000", state.OK}, // dscale 25 with zero padding
+		{"0001fffb00000014" + "0001", "", state.ScaleOutOfRange},         // 1E-20
+		{"0001ffff00000002" + "0001", "", state.InvalidFormat},           // digits beyond dscale
+		{"0001000a00000000" + "0001", "", state.Overflow},                // 10^40
+		{"0000000500000004", "0.0000", state.OK},                         // zero with weight
+	}
+
+	for _, tc := range testCases {
+		b, _ := hex.DecodeString(tc.b)
+		d := DecodePGNumeric(b)
+		switch {
+		case tc.e != state.OK:
+			if d.ErrorDetails() != tc.e.Error() {
+				t.Errorf("%s: expected error %v, got %s (%v)", tc.b, tc.e.Error(), d.StringFixed(), d.ErrorDetails())
+			}
+		case d.IsNaN():
+			t.Errorf("%s: unexpected error %v", tc.b, d.ErrorDetails())
+		case d.StringFixed() != tc.s:
+			t.Errorf("%s: expected %s, got %s", tc.b, tc.s, d.StringFixed())
+		}
+	}
+
+	if s := hex.EncodeToString(EncodePGNumeric(dec128.NaN(state.DivisionByZero))); s != "00000000c0000000" {
+		t.Errorf("expected NaN encoding, got %s", s)
+	}
+}
+
+func TestPGNumericRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(12345))
+	for range 10000 {
+		d := dec128.New(uint128.Uint128{Lo: r.Uint64(), Hi: r.Uint64() >> uint(r.Intn(64))}, uint8(r.Intn(int(dec128.MaxScale+1))), r.Intn(2) == 1)
+		x := DecodePGNumeric(AppendPGNumeric(nil, d))
+		if !x.Equal(d) || x.Scale() != d.Scale() {
+			t.Fatalf("round trip failed: %s != %s", x.StringFixed(), d.StringFixed())
+		}
+	}
+}
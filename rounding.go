@@ -249,3 +249,46 @@ func (d Dec128) Trunc(scale uint8) Dec128 {
 
 	return Dec128{coef: q, scale: scale, state: d.state}
 }
+
+// RoundingMode selects one of the rounding methods of Dec128.
+type RoundingMode uint8
+
+const (
+	// RoundingHalfAwayFromZero rounds as RoundHalfAwayFromZero.
+	RoundingHalfAwayFromZero RoundingMode = iota
+	// RoundingHalfTowardZero rounds as RoundHalfTowardZero.
+	RoundingHalfTowardZero
+	// RoundingBank rounds as RoundBank.
+	RoundingBank
+	// RoundingDown rounds as RoundDown.
+	RoundingDown
+	// RoundingUp rounds as RoundUp.
+	RoundingUp
+	// RoundingTowardZero rounds as RoundTowardZero.
+	RoundingTowardZero
+	// RoundingAwayFromZero rounds as RoundAwayFromZero.
+	RoundingAwayFromZero
+)
+
+// Round rounds the decimal to the specified scale using the given rounding mode.
+// An unknown mode returns NaN with the invalid format error.
+func (d Dec128) Round(scale uint8, mode RoundingMode) Dec128 {
+	switch mode {
+	case RoundingHalfAwayFromZero:
+		return d.RoundHalfAwayFromZero(scale)
+	case RoundingHalfTowardZero:
+		return d.RoundHalfTowardZero(scale)
+	case RoundingBank:
+		return d.RoundBank(scale)
+	case RoundingDown:
+		return d.RoundDown(scale)
+	case RoundingUp:
+		return d.RoundUp(scale)
+	case RoundingTowardZero:
+		return d.RoundTowardZero(scale)
+	case RoundingAwayFromZero:
+		return d.RoundAwayFromZero(scale)
+	default:
+		return Dec128{state: state.InvalidFormat}
+	}
+}
@@ -0,0 +1,91 @@
+package dec128
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/jokruger/dec128/state"
+	"github.com/jokruger/dec128/uint128"
+)
+
+// scanFloat converts the shortest decimal representation of f with the given bit size to Dec128, rounded once to scale with mode.
+func scanFloat(f float64, bitSize int, scale uint8, mode RoundingMode) Dec128 {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Dec128{state: state.NaN}
+	}
+
+	buf := [64]byte{}
+	b := strconv.AppendFloat(buf[:0], f, 'f', -1, bitSize)
+	if i := bytes.IndexByte(b, '.'); i >= 0 && len(b)-i-1 > int(MaxScale) {
+		// more digits after the decimal point than Dec128 can hold, round the exact decimal value
+		r, _ := new(big.Rat).SetString(string(b))
+		return fromBigRat(r, scale, mode)
+	}
+
+	return FromString(b).Round(scale, mode)
+}
+
+// fromBigInt converts i to Dec128. A nil i is converted to Zero.
+func fromBigInt(i *big.Int) Dec128 {
+	if i == nil || i.Sign() == 0 {
+		return Zero
+	}
+
+	coef, s := uint128.FromBigInt(new(big.Int).Abs(i))
+	if s >= state.Error {
+		return Dec128{state: s}
+	}
+
+	return New(coef, 0, i.Sign() < 0)
+}
+
+// fromBigRat converts r to Dec128 rounded to scale with mode. The result is canonical. A nil r is converted to Zero.
+func fromBigRat(r *big.Rat, scale uint8, mode RoundingMode) Dec128 {
+	switch {
+	case scale > MaxScale:
+		return Dec128{state: state.ScaleOutOfRange}
+	case mode > RoundingAwayFromZero:
+		return Dec128{state: state.InvalidFormat}
+	case r == nil || r.Sign() == 0:
+		return Zero
+	}
+
+	num := new(big.Int).Abs(r.Num())
+	num.Mul(num, Pow10Uint128[scale].BigInt())
+
+	q, m := num.QuoRem(num, r.Denom(), new(big.Int))
+	inexact := m.Sign() != 0
+	if roundAway(mode, r.Sign() < 0, m.Lsh(m, 1).Cmp(r.Denom()), inexact, q.Bit(0) == 1) {
+		q.Add(q, big.NewInt(1))
+	}
+
+	coef, s := uint128.FromBigInt(q)
+	if s >= state.Error {
+		return Dec128{state: s}
+	}
+
+	return New(coef, scale, r.Sign() < 0).Canonical()
+}
+
+// roundAway returns whether a value truncated toward zero must be rounded away from zero with mode.
+// half compares the dropped remainder with half a unit, inexact is true for a non-zero remainder, and odd for an odd truncated value.
+func roundAway(mode RoundingMode, neg bool, half int, inexact bool, odd bool) bool {
+	switch mode {
+	case RoundingHalfAwayFromZero:
+		return half >= 0
+	case RoundingHalfTowardZero:
+		return half > 0
+	case RoundingBank:
+		return half > 0 || (half == 0 && odd)
+	case RoundingDown:
+		return neg && inexact
+	case RoundingUp:
+		return !neg && inexact
+	case RoundingAwayFromZero:
+		return inexact
+	default:
+		return false
+	}
+}
@@ -0,0 +1,102 @@
+package dec128
+
+import (
+	"strconv"
+
+	"github.com/jokruger/dec128/state"
+	"github.com/jokruger/dec128/uint128"
+)
+
+// StringSci returns the string representation of the Dec128 following the to-scientific-string rules of the General Decimal Arithmetic specification.
+// Values with an adjusted exponent of -6 or more are written without exponent and with all digits of the scale (e.g. "123.45", "1.00", "0.001"),
+// smaller values use exponential notation with one digit before the decimal point (e.g. 0.00000012300 -> "1.2300E-7").
+// No digits are dropped, so the scale is preserved.
+// If the Dec128 is NaN, the string "NaN" is returned.
+func (d Dec128) StringSci() string {
+	buf := [MaxStrLen + 6]byte{}
+	return string(d.AppendSci(buf[:0]))
+}
+
+// StringEng returns the string representation of the Dec128 following the to-engineering-string rules of the General Decimal Arithmetic specification.
+// It is the same as StringSci, except that in exponential notation the exponent is a multiple of three
+// and the mantissa has one to three digits before the decimal point (e.g. 0.00000012300 -> "123.00E-9").
+// If the Dec128 is NaN, the string "NaN" is returned.
+func (d Dec128) StringEng() string {
+	buf := [MaxStrLen + 6]byte{}
+	return string(d.AppendEng(buf[:0]))
+}
+
+// AppendSci appends the scientific notation of the Dec128 (see StringSci) to buf and returns the extended buffer.
+func (d Dec128) AppendSci(buf []byte) []byte {
+	return d.appendExp(buf, false)
+}
+
+// AppendEng appends the engineering notation of the Dec128 (see StringEng) to buf and returns the extended buffer.
+func (d Dec128) AppendEng(buf []byte) []byte {
+	return d.appendExp(buf, true)
+}
+
+func (d Dec128) appendExp(buf []byte, eng bool) []byte {
+	if d.state >= state.Error {
+		return append(buf, NaNStr...)
+	}
+
+	tmp := [uint128.MaxStrLen]byte{}
+	coef := d.coef.StringToBuf(tmp[:])
+	if len(coef) == 0 {
+		coef = ZeroStrBytes
+	}
+
+	// number of coefficient digits before the decimal point, and before the point of the mantissa
+	left := len(coef) - int(d.scale)
+	var dot int
+	switch {
+	case left > -6:
+		// plain notation, the exponent is never positive
+		dot = left
+	case !eng:
+		dot = 1
+	case d.coef.IsZero():
+		dot = mod3(left+1) - 1
+	default:
+		dot = mod3(left-1) + 1
+	}
+
+	if d.state == state.Neg && !d.coef.IsZero() {
+		buf = append(buf, '-')
+	}
+
+	switch {
+	case dot <= 0:
+		buf = append(buf, '0', '.')
+		buf = appendZeros(buf, -dot)
+		buf = append(buf, coef...)
+	case dot >= len(coef):
+		buf = append(buf, coef...)
+		buf = appendZeros(buf, dot-len(coef))
+	default:
+		buf = append(buf, coef[:dot]...)
+		buf = append(buf, '.')
+		buf = append(buf, coef[dot:]...)
+	}
+
+	if exp := left - dot; exp != 0 {
+		if exp < 0 {
+			buf = append(buf, 'E', '-')
+			exp = -exp
+		} else {
+			buf = append(buf, 'E', '+')
+		}
+		buf = strconv.AppendInt(buf, int64(exp), 10)
+	}
+
+	return buf
+}
+
+// mod3 returns n modulo 3 in the range [0, 3).
+func mod3(n int) int {
+	if n %= 3; n < 0 {
+		n += 3
+	}
+	return n
+}
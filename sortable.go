@@ -0,0 +1,184 @@
+package dec128
+
+import (
+	"io"
+
+	"github.com/jokruger/dec128/state"
+	"github.com/jokruger/dec128/uint128"
+)
+
+// SortableKeyMaxSize is the maximum number of bytes of a key produced by AppendSortableKey.
+// AppendSortableKeyWithScale adds one more byte.
+const SortableKeyMaxSize = 1 + 1 + (uint128.MaxStrLen+1)/2 + 1
+
+// leading byte of sortable keys, in Compare order
+const (
+	keyNaN byte = iota
+	keyNeg
+	keyZero
+	keyPos
+)
+
+// bias of the exponent byte, exponents are between -MaxScale+1 and uint128.MaxStrLen
+const keyExpBias = 64
+
+// AppendSortableKey appends an order-preserving binary key of the Dec128 to buf and returns the extended buffer.
+// For valid values, comparing keys with bytes.Compare gives the same result as Compare, and equal values (e.g. 1.0 and 1.00) have identical keys.
+// Keys are self-delimiting, so they can be followed by other key components.
+// NaN values sort before all valid values and keep their error, so unlike Compare, which treats all NaNs as equal,
+// NaN keys with different errors are different and ordered by the state code.
+// It does not allocate if buf has enough capacity.
+func (d Dec128) AppendSortableKey(buf []byte) []byte {
+	switch {
+	case d.state >= state.Error:
+		return append(buf, keyNaN, byte(d.state))
+	case d.coef.IsZero():
+		return append(buf, keyZero)
+	}
+
+	// the value is 0.digits * 10^exp
+	tmp := [uint128.MaxStrLen]byte{}
+	digits := d.coef.StringToBuf(tmp[:])
+	exp := len(digits) - int(d.scale) + keyExpBias
+	digits = trimZeros(digits)
+
+	// negative values are stored with all bits inverted so that larger magnitudes sort first
+	var inv byte
+	if d.state == state.Neg {
+		inv = 0xff
+		buf = append(buf, keyNeg)
+	} else {
+		buf = append(buf, keyPos)
+	}
+	buf = append(buf, byte(exp)^inv)
+
+	// two digits per byte, biased by one so that the terminator sorts first
+	for i := 0; i < len(digits); i += 2 {
+		p := (digits[i] - '0') * 10
+		if i+1 < len(digits) {
+			p += digits[i+1] - '0'
+		}
+		buf = append(buf, (p+1)^inv)
+	}
+
+	return append(buf, inv)
+}
+
+// AppendSortableKeyWithScale appends an order-preserving binary key of the Dec128 that also keeps the scale to buf and returns the extended buffer.
+// Valid keys sort in Compare order, equal values with different scales sort by scale (e.g. 1.0 before 1.00); NaN keys sort as in AppendSortableKey.
+// It does not allocate if buf has enough capacity.
+func (d Dec128) AppendSortableKeyWithScale(buf []byte) []byte {
+	buf = d.AppendSortableKey(buf)
+	if d.state >= state.Error {
+		return buf
+	}
+	return append(buf, d.scale)
+}
+
+// DecodeSortableKey decodes a key produced by AppendSortableKey from the beginning of b.
+// It returns the Dec128 in canonical form and the number of bytes consumed.
+func DecodeSortableKey(b []byte) (Dec128, int, error) {
+	if len(b) == 0 {
+		return Zero, 0, io.ErrShortBuffer
+	}
+
+	var inv byte
+	switch b[0] {
+	case keyNaN:
+		if len(b) < 2 {
+			return Zero, 0, io.ErrShortBuffer
+		}
+		if state.State(b[1]) < state.Error {
+			return Zero, 0, state.InvalidFormat.Error()
+		}
+		return Dec128{state: state.State(b[1])}, 2, nil
+	case keyZero:
+		return Zero, 1, nil
+	case keyNeg:
+		inv = 0xff
+	case keyPos:
+	default:
+		return Zero, 0, state.InvalidFormat.Error()
+	}
+
+	if len(b) < 2 {
+		return Zero, 0, io.ErrShortBuffer
+	}
+	exp := int(b[1]^inv) - keyExpBias
+
+	var coef uint128.Uint128
+	var st state.State
+	var n int
+	pos := 2
+	for {
+		if pos >= len(b) {
+			return Zero, 0, io.ErrShortBuffer
+		}
+		p := b[pos] ^ inv
+		pos++
+		if p == 0 {
+			break
+		}
+		if p > 100 || n >= uint128.MaxStrLen {
+			return Zero, 0, state.InvalidFormat.Error()
+		}
+		p--
+
+		// digits never end with zero, so a trailing zero in the last pair is padding
+		if p%10 == 0 && pos < len(b) && b[pos] == inv {
+			coef, st = coef.MulAdd64(10, uint64(p/10))
+			n++
+		} else {
+			coef, st = coef.MulAdd64(100, uint64(p))
+			n += 2
+		}
+		if st >= state.Error {
+			return Zero, 0, state.InvalidFormat.Error()
+		}
+	}
+
+	if n == 0 {
+		return Zero, 0, state.InvalidFormat.Error()
+	}
+
+	scale := n - exp
+	switch {
+	case scale > int(MaxScale):
+		return Zero, 0, state.InvalidFormat.Error()
+	case scale < 0:
+		if -scale >= len(Pow10Uint128) {
+			return Zero, 0, state.InvalidFormat.Error()
+		}
+		coef, st = coef.Mul(Pow10Uint128[-scale])
+		if st >= state.Error {
+			return Zero, 0, state.InvalidFormat.Error()
+		}
+		scale = 0
+	}
+
+	d := Dec128{coef: coef, scale: uint8(scale)}
+	if inv != 0 {
+		d.state = state.Neg
+	}
+
+	return d, pos, nil
+}
+
+// DecodeSortableKeyWithScale decodes a key produced by AppendSortableKeyWithScale from the beginning of b.
+// It returns the Dec128 with its original scale and the number of bytes consumed.
+func DecodeSortableKeyWithScale(b []byte) (Dec128, int, error) {
+	d, n, err := DecodeSortableKey(b)
+	if err != nil || d.state >= state.Error {
+		return d, n, err
+	}
+	if n >= len(b) {
+		return Zero, 0, io.ErrShortBuffer
+	}
+
+	scale := b[n]
+	if scale < d.scale || scale > MaxScale {
+		return Zero, 0, state.InvalidFormat.Error()
+	}
+
+	return d.Rescale(scale), n + 1, nil
+}
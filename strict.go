@@ -0,0 +1,90 @@
+package dec128
+
+import "github.com/jokruger/dec128/state"
+
+// Strict is a Dec128 with strict JSON decoding for fields where a silently defaulted value is a bug (e.g. payment amounts).
+// UnmarshalJSON accepts only non-empty JSON strings holding a valid number, and returns an error for null, "", "NaN" and bare JSON numbers.
+// It is encoded the same way as Dec128. Convert with Strict(d) and Dec128(s). Use StrictLimited to also limit the scale and magnitude.
+//
+// A missing key is not an error: encoding/json does not call UnmarshalJSON for it, so the field keeps its previous value (zero for a new struct).
+// To detect missing keys, use a *Strict field and check it for nil after decoding; note that a JSON null also leaves the pointer nil.
+type Strict Dec128
+
+// StrictLimits defines the limits checked by StrictLimited. Implement it on an empty struct type, e.g.
+//
+//	type Amount struct{}
+//
+//	func (Amount) MaxScale() uint8        { return 2 }
+//	func (Amount) MaxAbs() dec128.Dec128 { return dec128.FromInt64(1000000) }
+type StrictLimits interface {
+	// MaxScale returns the maximum number of significant digits after the decimal point. Trailing zeros are ignored.
+	MaxScale() uint8
+
+	// MaxAbs returns the maximum absolute value, or zero for no magnitude limit.
+	MaxAbs() Dec128
+}
+
+// StrictLimited is a Strict that also rejects values outside the limits of L (e.g. StrictLimited[Amount]).
+// Missing keys are handled as for Strict. Convert with StrictLimited[L](d) and Dec128(s).
+type StrictLimited[L StrictLimits] Dec128
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s Strict) MarshalJSON() ([]byte, error) {
+	return Dec128(s).MarshalJSON()
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It returns state.InvalidFormat for null, empty and unquoted values, and state.NaN for "NaN".
+func (s *Strict) UnmarshalJSON(data []byte) error {
+	return (*Dec128)(s).unmarshalStrict(data, MaxScale, Zero)
+}
+
+// String returns the string representation of the value.
+func (s Strict) String() string {
+	return Dec128(s).String()
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s StrictLimited[L]) MarshalJSON() ([]byte, error) {
+	return Dec128(s).MarshalJSON()
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It returns the errors of Strict.UnmarshalJSON, state.ScaleOutOfRange for values with too many digits after the decimal point,
+// and state.Overflow for values above the magnitude limit.
+func (s *StrictLimited[L]) UnmarshalJSON(data []byte) error {
+	var l L
+	return (*Dec128)(s).unmarshalStrict(data, l.MaxScale(), l.MaxAbs())
+}
+
+// String returns the string representation of the value.
+func (s StrictLimited[L]) String() string {
+	return Dec128(s).String()
+}
+
+// unmarshalStrict decodes a non-empty JSON string into d and checks it against maxScale and maxAbs (zero for no limit).
+func (d *Dec128) unmarshalStrict(data []byte, maxScale uint8, maxAbs Dec128) error {
+	sz := len(data)
+	if sz < 3 || data[0] != '"' || data[sz-1] != '"' {
+		return state.InvalidFormat.Error()
+	}
+	data = data[1 : sz-1]
+
+	if string(data) == NaNStr {
+		return state.NaN.Error()
+	}
+
+	t := FromString(data)
+	switch {
+	case t.IsNaN():
+		return t.ErrorDetails()
+	case t.Canonical().scale > maxScale:
+		return state.ScaleOutOfRange.Error()
+	case !maxAbs.IsZero() && t.Abs().GreaterThan(maxAbs):
+		return state.Overflow.Error()
+	}
+
+	*d = t
+
+	return nil
+}
@@ -0,0 +1,226 @@
+// Package swift formats, parses and validates Dec128 amounts under the rules of ISO 20022 and SWIFT MT messages.
+//
+// ISO 20022 amounts (ActiveCurrencyAndAmount and ActiveOrHistoricCurrencyAndAmount) are xs:decimal values
+// with at most 18 digits, at most 5 of them after the decimal point, no negative values,
+// and no more fractional digits than the minor units of the currency (see dec128.CurrencyMinorUnits).
+//
+// SWIFT MT amounts (e.g. the 15d part of field 32A) use a mandatory decimal comma without grouping or sign ("1234,56", "1234,"),
+// a maximum length that includes the comma, and no more digits after the comma than the minor units of the currency.
+package swift
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jokruger/dec128"
+	"github.com/jokruger/dec128/state"
+	"github.com/jokruger/dec128/uint128"
+)
+
+// ISO 20022 amount limits.
+const (
+	// ISOTotalDigits is the maximum number of digits of an ISO 20022 amount.
+	ISOTotalDigits = 18
+
+	// ISOFractionDigits is the maximum number of digits after the decimal point of an ISO 20022 amount.
+	ISOFractionDigits = 5
+)
+
+// MTLength is the maximum length of the common 15d amount format of SWIFT MT fields, including the decimal comma.
+// Fields with a different length (e.g. 17d of field 19) pass their own length to the MT functions.
+const MTLength = 15
+
+// Errors returned when an amount violates a rule. They are wrapped with the actual and allowed values and can be tested with errors.Is.
+var (
+	ErrCurrency       = errors.New("swift: invalid currency code")
+	ErrNegative       = errors.New("swift: negative amount")
+	ErrTotalDigits    = errors.New("swift: too many digits")
+	ErrFractionDigits = errors.New("swift: too many fractional digits")
+	ErrLength         = errors.New("swift: amount too long")
+)
+
+// ValidateISO returns an error if d is not a valid ISO 20022 amount in the given currency.
+// Trailing zeros after the decimal point are not counted, as for xs:decimal values.
+func ValidateISO(d dec128.Dec128, currency string) error {
+	_, err := checkISO(d, currency)
+	return err
+}
+
+// FormatISO returns d as the text of an ISO 20022 amount in the given currency (e.g. "1234.5").
+// It returns an error if d is not a valid amount.
+func FormatISO(d dec128.Dec128, currency string) (string, error) {
+	buf := [dec128.MaxStrLen]byte{}
+	b, err := AppendISO(buf[:0], d, currency)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// AppendISO appends the text of an ISO 20022 amount in the given currency to buf and returns the extended buffer.
+// Trailing zeros after the decimal point are removed. It returns an error if d is not a valid amount.
+func AppendISO(buf []byte, d dec128.Dec128, currency string) ([]byte, error) {
+	d, err := checkISO(d, currency)
+	if err != nil {
+		return buf, err
+	}
+	return d.AppendFormat(buf, dec128.FormatOptions{}), nil
+}
+
+// ParseISO parses the text of an ISO 20022 amount in the given currency.
+// It accepts the format of dec128.FromString and returns an error if the value is not a valid amount.
+func ParseISO(s string, currency string) (dec128.Dec128, error) {
+	if s == "" {
+		return dec128.Zero, state.InvalidFormat.Error()
+	}
+
+	d := dec128.FromString(s)
+	if _, err := checkISO(d, currency); err != nil {
+		return dec128.Zero, err
+	}
+
+	return d, nil
+}
+
+// ValidateMT returns an error if d is not a valid SWIFT MT amount in the given currency for a field of the given maximum length.
+// Trailing zeros after the decimal point are not counted, as they are not written by AppendMT.
+func ValidateMT(d dec128.Dec128, currency string, length int) error {
+	_, err := checkMT(d, currency, length)
+	return err
+}
+
+// FormatMT returns d as a SWIFT MT amount in the given currency (e.g. "1234,56" or "1234,").
+// It returns an error if d is not a valid amount for a field of the given maximum length.
+func FormatMT(d dec128.Dec128, currency string, length int) (string, error) {
+	buf := [dec128.MaxStrLen + 1]byte{}
+	b, err := AppendMT(buf[:0], d, currency, length)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// AppendMT appends d as a SWIFT MT amount in the given currency to buf and returns the extended buffer.
+// Trailing zeros after the decimal comma are removed, the comma itself is always written.
+// It returns an error if d is not a valid amount for a field of the given maximum length.
+func AppendMT(buf []byte, d dec128.Dec128, currency string, length int) ([]byte, error) {
+	d, err := checkMT(d, currency, length)
+	if err != nil {
+		return buf, err
+	}
+
+	buf = d.AppendFormat(buf, dec128.FormatOptions{DecimalSeparator: ','})
+	if d.Scale() == 0 {
+		buf = append(buf, ',')
+	}
+
+	return buf, nil
+}
+
+// ParseMT parses a SWIFT MT amount in the given currency for a field of the given maximum length.
+// The text must be digits with exactly one comma and at least one digit before it.
+// Unlike ValidateMT, every digit after the comma counts against the minor units of the currency, as in the SWIFT network validation.
+func ParseMT(s string, currency string, length int) (dec128.Dec128, error) {
+	minor, ok := dec128.CurrencyMinorUnits(currency)
+	if !ok {
+		return dec128.Zero, fmt.Errorf("%w: %q", ErrCurrency, currency)
+	}
+	if len(s) > length {
+		return dec128.Zero, fmt.Errorf("%w: %d characters, maximum %d", ErrLength, len(s), length)
+	}
+
+	comma := -1
+	for i := range len(s) {
+		switch c := s[i]; {
+		case c == ',' && comma < 0:
+			comma = i
+		case c < '0' || c > '9':
+			return dec128.Zero, state.InvalidFormat.Error()
+		}
+	}
+	if comma <= 0 {
+		return dec128.Zero, state.InvalidFormat.Error()
+	}
+
+	if n := len(s) - comma - 1; n > int(minor) {
+		return dec128.Zero, fmt.Errorf("%w: %d, maximum %d for %s", ErrFractionDigits, n, minor, currency)
+	}
+
+	buf := [dec128.MaxStrLen]byte{}
+	b := append(buf[:0], s[:comma]...)
+	if comma < len(s)-1 {
+		b = append(b, '.')
+		b = append(b, s[comma+1:]...)
+	}
+
+	d := dec128.FromString(b)
+	if d.IsNaN() {
+		return dec128.Zero, d.ErrorDetails()
+	}
+
+	return d, nil
+}
+
+// checkISO returns the canonical d if it is a valid ISO 20022 amount in the given currency.
+func checkISO(d dec128.Dec128, currency string) (dec128.Dec128, error) {
+	d, minor, err := check(d, currency)
+	if err != nil {
+		return d, err
+	}
+
+	if n := int(d.Scale()); n > min(int(minor), ISOFractionDigits) {
+		if n > ISOFractionDigits {
+			return d, fmt.Errorf("%w: %d, maximum %d", ErrFractionDigits, n, ISOFractionDigits)
+		}
+		return d, fmt.Errorf("%w: %d, maximum %d for %s", ErrFractionDigits, n, minor, currency)
+	}
+
+	if n := digits(d.Coefficient()); n > ISOTotalDigits {
+		return d, fmt.Errorf("%w: %d, maximum %d", ErrTotalDigits, n, ISOTotalDigits)
+	}
+
+	return d, nil
+}
+
+// checkMT returns the canonical d if it is a valid SWIFT MT amount in the given currency for a field of the given maximum length.
+func checkMT(d dec128.Dec128, currency string, length int) (dec128.Dec128, error) {
+	d, minor, err := check(d, currency)
+	if err != nil {
+		return d, err
+	}
+
+	if n := int(d.Scale()); n > int(minor) {
+		return d, fmt.Errorf("%w: %d, maximum %d for %s", ErrFractionDigits, n, minor, currency)
+	}
+
+	// integer digits (at least one), comma and fractional digits
+	n := max(digits(d.Coefficient()), int(d.Scale())+1) + 1
+	if n > length {
+		return d, fmt.Errorf("%w: %d characters, maximum %d", ErrLength, n, length)
+	}
+
+	return d, nil
+}
+
+// check returns the canonical d and the minor units of currency, or an error if currency is not valid or d is NaN or negative.
+func check(d dec128.Dec128, currency string) (dec128.Dec128, uint8, error) {
+	minor, ok := dec128.CurrencyMinorUnits(currency)
+	switch {
+	case !ok:
+		return d, 0, fmt.Errorf("%w: %q", ErrCurrency, currency)
+	case d.IsNaN():
+		return d, 0, d.ErrorDetails()
+	case d.IsNegative():
+		return d, 0, ErrNegative
+	}
+	return d.Canonical(), minor, nil
+}
+
+// digits returns the number of decimal digits of coef, or 1 for zero.
+func digits(coef uint128.Uint128) int {
+	n := 1
+	for n < len(dec128.Pow10Uint128) && coef.Compare(dec128.Pow10Uint128[n]) >= 0 {
+		n++
+	}
+	return n
+}
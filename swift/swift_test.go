@@ -0,0 +1,166 @@
+package swift
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jokruger/dec128"
+	"github.com/jokruger/dec128/state"
+)
+
+func TestISO(t *testing.T) {
+	type testCase struct {
+		i   string
+		ccy string
+		s   string
+		e   error
+	}
+
+	testCases := [...]testCase{
+		{"0", "EUR", "0", nil},
+		{"1234.50", "EUR", "1234.5", nil},
+		{"1234.56", "USD", "1234.56", nil},
+		{"1000", "JPY", "1000", nil},
+		{"0.123", "KWD", "0.123", nil},
+		{"999999999999999999", "JPY", "999999999999999999", nil},
+		{"9999999999999999.99", "EUR", "9999999999999999.99", nil},
+		{"0.00001", "XXX", "", ErrFractionDigits},
+		{"1.2345", "CLF", "1.2345", nil},
+		{"1.234", "EUR", "", ErrFractionDigits},
+		{"1.5", "JPY", "", ErrFractionDigits},
+		{"0.123456", "CLF", "", ErrFractionDigits},
+		{"1000000000000000000", "JPY", "", ErrTotalDigits},
+		{"99999999999999999.99", "EUR", "", ErrTotalDigits},
+		{"-1", "EUR", "", ErrNegative},
+		{"1", "eur", "", ErrCurrency},
+		{"NaN", "EUR", "", state.InvalidFormat.Error()},
+	}
+
+	for _, tc := range testCases {
+		d := dec128.FromString(tc.i)
+
+		s, err := FormatISO(d, tc.ccy)
+		switch {
+		case tc.e != nil:
+			if !errors.Is(err, tc.e) || !errors.Is(ValidateISO(d, tc.ccy), tc.e) {
+				t.Errorf("%s %s: expected error %v, got: %v (%s)", tc.i, tc.ccy, tc.e, err, s)
+			}
+			if _, err := ParseISO(tc.i, tc.ccy); !errors.Is(err, tc.e) {
+				t.Errorf("%s %s: expected parse error %v, got: %v", tc.i, tc.ccy, tc.e, err)
+			}
+			continue
+		case err != nil:
+			t.Errorf("%s %s: unexpected error: %v", tc.i, tc.ccy, err)
+			continue
+		case s != tc.s:
+			t.Errorf("%s %s: expected %s, got: %s", tc.i, tc.ccy, tc.s, s)
+		}
+
+		r, err := ParseISO(tc.i, tc.ccy)
+		if err != nil || !r.Equal(d) {
+			t.Errorf("%s %s: expected %s, got: %s (%v)", tc.i, tc.ccy, d.String(), r.String(), err)
+		}
+	}
+
+	if err := ValidateISO(dec128.NaN(state.DivisionByZero), "EUR"); err != state.DivisionByZero.Error() {
+		t.Errorf("expected division by zero error, got: %v", err)
+	}
+	if _, err := ParseISO("", "EUR"); err != state.InvalidFormat.Error() {
+		t.Errorf("expected invalid format error, got: %v", err)
+	}
+}
+
+func TestMT(t *testing.T) {
+	type testCase struct {
+		i   string
+		ccy string
+		s   string
+		e   error
+	}
+
+	testCases := [...]testCase{
+		{"0", "EUR", "0,", nil},
+		{"1234.56", "EUR", "1234,56", nil},
+		{"1234.50", "EUR", "1234,5", nil},
+		{"0.05", "USD", "0,05", nil},
+		{"1000", "JPY", "1000,", nil},
+		{"0.123", "BHD", "0,123", nil},
+		{"12345678901234", "EUR", "12345678901234,", nil},
+		{"123456789012.34", "EUR", "123456789012,34", nil},
+		{"123456789012345", "EUR", "", ErrLength},
+		{"1234567890123.45", "EUR", "", ErrLength},
+		{"1.234", "EUR", "", ErrFractionDigits},
+		{"1.5", "JPY", "", ErrFractionDigits},
+		{"-1", "EUR", "", ErrNegative},
+		{"1", "E1R", "", ErrCurrency},
+	}
+
+	for _, tc := range testCases {
+		d := dec128.FromString(tc.i)
+
+		s, err := FormatMT(d, tc.ccy, MTLength)
+		switch {
+		case tc.e != nil:
+			if !errors.Is(err, tc.e) || !errors.Is(ValidateMT(d, tc.ccy, MTLength), tc.e) {
+				t.Errorf("%s %s: expected error %v, got: %v (%s)", tc.i, tc.ccy, tc.e, err, s)
+			}
+			continue
+		case err != nil:
+			t.Errorf("%s %s: unexpected error: %v", tc.i, tc.ccy, err)
+			continue
+		case s != tc.s:
+			t.Errorf("%s %s: expected %s, got: %s", tc.i, tc.ccy, tc.s, s)
+		}
+
+		r, err := ParseMT(s, tc.ccy, MTLength)
+		if err != nil || !r.Equal(d) {
+			t.Errorf("%s %s: expected %s, got: %s (%v)", s, tc.ccy, d.String(), r.String(), err)
+		}
+	}
+
+	if s, err := FormatMT(dec128.FromString("1234567890123456"), "EUR", 17); err != nil || s != "1234567890123456," {
+		t.Errorf("expected 1234567890123456, with length 17, got: %s (%v)", s, err)
+	}
+}
+
+func TestParseMT(t *testing.T) {
+	type testCase struct {
+		s string
+		v string
+		e error
+	}
+
+	testCases := [...]testCase{
+		{"1234,56", "1234.56", nil},
+		{"1234,", "1234", nil},
+		{"0,5", "0.5", nil},
+		{"001,10", "1.1", nil},
+		{"1,000", "", ErrFractionDigits},
+		{"1234567890123,45", "", ErrLength},
+		{"1234", "", state.InvalidFormat.Error()},
+		{",5", "", state.InvalidFormat.Error()},
+		{"1.5", "", state.InvalidFormat.Error()},
+		{"1,2,3", "", state.InvalidFormat.Error()},
+		{"-1,5", "", state.InvalidFormat.Error()},
+		{"1 000,00", "", state.InvalidFormat.Error()},
+		{"", "", state.InvalidFormat.Error()},
+	}
+
+	for _, tc := range testCases {
+		d, err := ParseMT(tc.s, "EUR", MTLength)
+		switch {
+		case tc.e != nil:
+			if !errors.Is(err, tc.e) {
+				t.Errorf("%q: expected error %v, got: %v (%s)", tc.s, tc.e, err, d.String())
+			}
+		case err != nil:
+			t.Errorf("%q: unexpected error: %v", tc.s, err)
+		case d.String() != tc.v:
+			t.Errorf("%q: expected %s, got: %s", tc.s, tc.v, d.String())
+		}
+	}
+
+	if _, err := ParseMT("1,5", "JPY", MTLength); err == nil || err.Error() != "swift: too many fractional digits: 1, maximum 0 for JPY" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package words
+
+import "github.com/jokruger/dec128/uint128"
+
+type english struct{}
+
+// English spells numbers in English using the short scale (e.g. "one thousand two hundred thirty-four").
+var English Language = english{}
+
+var (
+	enSmall = [...]string{
+		"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+		"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen",
+	}
+	enTens = [...]string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+
+	// 10^(3*i) names up to 10^36
+	enScales = [...]string{
+		"", "thousand", "million", "billion", "trillion", "quadrillion", "quintillion",
+		"sextillion", "septillion", "octillion", "nonillion", "decillion", "undecillion",
+	}
+)
+
+// AppendCardinal implements the Language interface.
+func (english) AppendCardinal(buf []byte, n uint128.Uint128, noun bool) []byte {
+	if n.IsZero() {
+		return append(buf, enSmall[0]...)
+	}
+
+	gs := groups(n, 1000, make([]uint64, 0, len(enScales)))
+	first := true
+	for i := len(gs) - 1; i >= 0; i-- {
+		if gs[i] == 0 {
+			continue
+		}
+		if !first {
+			buf = append(buf, ' ')
+		}
+		first = false
+		buf = enUnder1000(buf, gs[i])
+		if i > 0 {
+			buf = append(buf, ' ')
+			buf = append(buf, enScales[i]...)
+		}
+	}
+
+	return buf
+}
+
+// Vocabulary implements the Language interface.
+func (english) Vocabulary() Vocabulary {
+	return Vocabulary{
+		Minus: "minus",
+		And:   "and",
+		Point: "point",
+		Major: Unit{"dollar", "dollars"},
+		Minor: Unit{"cent", "cents"},
+	}
+}
+
+// called only when 0 < n < 1000
+func enUnder1000(buf []byte, n uint64) []byte {
+	h, r := n/100, n%100
+
+	if h > 0 {
+		buf = append(buf, enSmall[h]...)
+		buf = append(buf, " hundred"...)
+		if r == 0 {
+			return buf
+		}
+		buf = append(buf, ' ')
+	}
+
+	if r < 20 {
+		return append(buf, enSmall[r]...)
+	}
+
+	buf = append(buf, enTens[r/10]...)
+	if r%10 > 0 {
+		buf = append(buf, '-')
+		buf = append(buf, enSmall[r%10]...)
+	}
+
+	return buf
+}
@@ -0,0 +1,120 @@
+package words
+
+import "github.com/jokruger/dec128/uint128"
+
+type german struct{}
+
+// German spells numbers in German using the long scale (e.g. "eintausendzweihundertvierunddreißig").
+var German Language = german{}
+
+var (
+	deSmall = [...]string{
+		"null", "eins", "zwei", "drei", "vier", "fünf", "sechs", "sieben", "acht", "neun",
+		"zehn", "elf", "zwölf", "dreizehn", "vierzehn", "fünfzehn", "sechzehn", "siebzehn", "achtzehn", "neunzehn",
+	}
+	deTens = [...]string{"", "", "zwanzig", "dreißig", "vierzig", "fünfzig", "sechzig", "siebzig", "achtzig", "neunzig"}
+
+	// 10^(3*i) names for i >= 2 up to 10^36
+	deScales = [...]Unit{
+		{}, {},
+		{"Million", "Millionen"}, {"Milliarde", "Milliarden"},
+		{"Billion", "Billionen"}, {"Billiarde", "Billiarden"},
+		{"Trillion", "Trillionen"}, {"Trilliarde", "Trilliarden"},
+		{"Quadrillion", "Quadrillionen"}, {"Quadrilliarde", "Quadrilliarden"},
+		{"Quintillion", "Quintillionen"}, {"Quintilliarde", "Quintilliarden"},
+		{"Sextillion", "Sextillionen"},
+	}
+)
+
+// AppendCardinal implements the Language interface.
+// Numbers below one million are written as a single word, larger scales are separate words.
+func (german) AppendCardinal(buf []byte, n uint128.Uint128, noun bool) []byte {
+	if n.IsZero() {
+		return append(buf, deSmall[0]...)
+	}
+
+	gs := groups(n, 1000, make([]uint64, 0, len(deScales)))
+	first := true
+	for i := len(gs) - 1; i >= 2; i-- {
+		if gs[i] == 0 {
+			continue
+		}
+		if !first {
+			buf = append(buf, ' ')
+		}
+		first = false
+		buf = deUnder1000(buf, gs[i], "eine")
+		buf = appendUnit(buf, deScales[i], gs[i] == 1)
+	}
+
+	var thousands, units uint64
+	if len(gs) > 1 {
+		thousands = gs[1]
+	}
+	units = gs[0]
+	if thousands == 0 && units == 0 {
+		return buf
+	}
+
+	if !first {
+		buf = append(buf, ' ')
+	}
+	if thousands > 0 {
+		buf = deUnder1000(buf, thousands, "ein")
+		buf = append(buf, "tausend"...)
+	}
+	if units > 0 {
+		if noun {
+			buf = deUnder1000(buf, units, "ein")
+		} else {
+			buf = deUnder1000(buf, units, "eins")
+		}
+	}
+
+	return buf
+}
+
+// Vocabulary implements the Language interface.
+func (german) Vocabulary() Vocabulary {
+	return Vocabulary{
+		Minus: "minus",
+		And:   "und",
+		Point: "Komma",
+		Major: Unit{"Euro", "Euro"},
+		Minor: Unit{"Cent", "Cent"},
+	}
+}
+
+// called only when 0 < n < 1000, one is the form used when the number ends with 01
+func deUnder1000(buf []byte, n uint64, one string) []byte {
+	h, r := n/100, n%100
+
+	if h > 0 {
+		if h == 1 {
+			buf = append(buf, "ein"...)
+		} else {
+			buf = append(buf, deSmall[h]...)
+		}
+		buf = append(buf, "hundert"...)
+	}
+
+	switch {
+	case r == 0:
+		return buf
+	case r == 1:
+		return append(buf, one...)
+	case r < 20:
+		return append(buf, deSmall[r]...)
+	}
+
+	if u := r % 10; u > 0 {
+		if u == 1 {
+			buf = append(buf, "ein"...)
+		} else {
+			buf = append(buf, deSmall[u]...)
+		}
+		buf = append(buf, "und"...)
+	}
+
+	return append(buf, deTens[r/10]...)
+}
@@ -0,0 +1,125 @@
+package words
+
+import "github.com/jokruger/dec128/uint128"
+
+type spanish struct{}
+
+// Spanish spells numbers in Spanish using the long scale (e.g. "mil doscientos treinta y cuatro").
+var Spanish Language = spanish{}
+
+var (
+	esSmall = [...]string{
+		"cero", "uno", "dos", "tres", "cuatro", "cinco", "seis", "siete", "ocho", "nueve",
+		"diez", "once", "doce", "trece", "catorce", "quince", "dieciséis", "diecisiete", "dieciocho", "diecinueve",
+		"veinte", "veintiuno", "veintidós", "veintitrés", "veinticuatro", "veinticinco", "veintiséis", "veintisiete", "veintiocho", "veintinueve",
+	}
+	esTens     = [...]string{"", "", "", "treinta", "cuarenta", "cincuenta", "sesenta", "setenta", "ochenta", "noventa"}
+	esHundreds = [...]string{
+		"", "ciento", "doscientos", "trescientos", "cuatrocientos", "quinientos", "seiscientos", "setecientos", "ochocientos", "novecientos",
+	}
+
+	// 10^(6*i) names for i >= 1 up to 10^36
+	esScales = [...]Unit{
+		{},
+		{"millón", "millones"},
+		{"billón", "billones"},
+		{"trillón", "trillones"},
+		{"cuatrillón", "cuatrillones"},
+		{"quintillón", "quintillones"},
+		{"sextillón", "sextillones"},
+	}
+)
+
+// AppendCardinal implements the Language interface.
+func (spanish) AppendCardinal(buf []byte, n uint128.Uint128, noun bool) []byte {
+	if n.IsZero() {
+		return append(buf, esSmall[0]...)
+	}
+
+	gs := groups(n, 1000000, make([]uint64, 0, len(esScales)))
+	first := true
+	for i := len(gs) - 1; i >= 0; i-- {
+		if gs[i] == 0 {
+			continue
+		}
+		if !first {
+			buf = append(buf, ' ')
+		}
+		first = false
+		if i == 0 {
+			buf = esUnder1000000(buf, gs[i], noun)
+		} else {
+			buf = esUnder1000000(buf, gs[i], true)
+			buf = appendUnit(buf, esScales[i], gs[i] == 1)
+		}
+	}
+
+	return buf
+}
+
+// Vocabulary implements the Language interface.
+func (spanish) Vocabulary() Vocabulary {
+	return Vocabulary{
+		Minus: "menos",
+		And:   "con",
+		Point: "coma",
+		Major: Unit{"peso", "pesos"},
+		Minor: Unit{"centavo", "centavos"},
+	}
+}
+
+// called only when 0 < n < 1000000, apocope selects the shortened form used in front of nouns (e.g. "un", "veintiún")
+func esUnder1000000(buf []byte, n uint64, apocope bool) []byte {
+	t, r := n/1000, n%1000
+
+	if t > 0 {
+		if t > 1 {
+			buf = esUnder1000(buf, t, true)
+			buf = append(buf, ' ')
+		}
+		buf = append(buf, "mil"...)
+		if r == 0 {
+			return buf
+		}
+		buf = append(buf, ' ')
+	}
+
+	return esUnder1000(buf, r, apocope)
+}
+
+// called only when 0 < n < 1000
+func esUnder1000(buf []byte, n uint64, apocope bool) []byte {
+	h, r := n/100, n%100
+
+	if h > 0 {
+		if h == 1 && r == 0 {
+			return append(buf, "cien"...)
+		}
+		buf = append(buf, esHundreds[h]...)
+		if r == 0 {
+			return buf
+		}
+		buf = append(buf, ' ')
+	}
+
+	switch {
+	case apocope && r == 1:
+		return append(buf, "un"...)
+	case apocope && r == 21:
+		return append(buf, "veintiún"...)
+	case r < 30:
+		return append(buf, esSmall[r]...)
+	}
+
+	buf = append(buf, esTens[r/10]...)
+	if u := r % 10; u > 0 {
+		buf = append(buf, " y "...)
+		if apocope && u == 1 {
+			buf = append(buf, "un"...)
+		} else {
+			buf = append(buf, esSmall[u]...)
+		}
+	}
+
+	return buf
+}
@@ -0,0 +1,206 @@
+// Package words spells out Dec128 amounts in words (e.g. for cheque printing).
+package words
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jokruger/dec128"
+	"github.com/jokruger/dec128/state"
+	"github.com/jokruger/dec128/uint128"
+)
+
+// FractionStyle defines how the fractional part of an amount is spelled out.
+type FractionStyle uint8
+
+const (
+	// FractionSlash renders the fractional part as a fraction of the minor unit (e.g. "one and 56/100").
+	FractionSlash FractionStyle = iota
+	// FractionPoint spells the fractional digits one by one (e.g. "one point five six").
+	FractionPoint
+	// FractionCurrency spells the amount as major and minor currency units (e.g. "one dollar and fifty-six cents").
+	FractionCurrency
+)
+
+// Vocabulary holds the connecting words and the default currency units of a language.
+type Vocabulary struct {
+	Minus string // word for the negative sign (e.g. "minus")
+	And   string // conjunction between the integer and fractional parts (e.g. "and")
+	Point string // word for the decimal point (e.g. "point")
+
+	Major Unit // default major currency unit (e.g. "dollar")
+	Minor Unit // default minor currency unit (e.g. "cent")
+}
+
+// Unit is the singular and plural name of a currency unit.
+type Unit struct {
+	One  string
+	Many string
+}
+
+// Language converts non-negative integers to words.
+type Language interface {
+	// AppendCardinal appends the cardinal number n in words to buf.
+	// If noun is true the form used in front of a noun is produced (e.g. Spanish "un" instead of "uno").
+	AppendCardinal(buf []byte, n uint128.Uint128, noun bool) []byte
+
+	// Vocabulary returns the connecting words of the language.
+	Vocabulary() Vocabulary
+}
+
+// Options controls how an amount is spelled out. The zero value spells out English with the FractionSlash style.
+type Options struct {
+	// Language is the language to use. If nil, English is used.
+	Language Language
+
+	// Fraction defines how the fractional part is spelled out.
+	Fraction FractionStyle
+
+	// MinorDigits is the number of fractional digits used by FractionSlash and FractionCurrency. If zero, 2 is used.
+	// The amount is rounded half away from zero to MinorDigits.
+	MinorDigits uint8
+
+	// Major and Minor override the currency units of the language for FractionCurrency.
+	Major Unit
+	Minor Unit
+
+	// Capitalize upper-cases the first letter of the result.
+	Capitalize bool
+}
+
+// Spell returns d spelled out in words according to opts.
+// It returns an error if d is NaN or MinorDigits is out of range.
+func Spell(d dec128.Dec128, opts Options) (string, error) {
+	buf, err := AppendSpell(make([]byte, 0, 128), d, opts)
+	if err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// AppendSpell appends d spelled out in words according to opts to buf and returns the extended buffer.
+// It returns an error if d is NaN or MinorDigits is out of range.
+func AppendSpell(buf []byte, d dec128.Dec128, opts Options) ([]byte, error) {
+	if d.IsNaN() {
+		return buf, d.ErrorDetails()
+	}
+
+	lang := opts.Language
+	if lang == nil {
+		lang = English
+	}
+	voc := lang.Vocabulary()
+
+	minor := opts.MinorDigits
+	if minor == 0 {
+		minor = 2
+	}
+	if minor > dec128.MaxScale {
+		return buf, state.ScaleOutOfRange.Error()
+	}
+
+	if opts.Fraction != FractionPoint {
+		d = d.RoundHalfAwayFromZero(minor)
+	}
+
+	start := len(buf)
+
+	if d.IsNegative() {
+		buf = append(buf, voc.Minus...)
+		buf = append(buf, ' ')
+	}
+
+	// split coefficient into integer and fractional parts, the fractional part always fits into uint64
+	scale := d.Scale()
+	ipart, frac, _ := d.Coefficient().QuoRem64(dec128.Pow10Uint64[scale])
+
+	switch opts.Fraction {
+	case FractionPoint:
+		buf = lang.AppendCardinal(buf, ipart, false)
+		if frac > 0 {
+			for frac%10 == 0 {
+				frac /= 10
+				scale--
+			}
+			buf = append(buf, ' ')
+			buf = append(buf, voc.Point...)
+			for i := int(scale) - 1; i >= 0; i-- {
+				buf = append(buf, ' ')
+				buf = lang.AppendCardinal(buf, uint128.FromUint64(frac/dec128.Pow10Uint64[i]%10), false)
+			}
+		}
+
+	case FractionCurrency:
+		major, minorUnit := voc.Major, voc.Minor
+		if opts.Major != (Unit{}) {
+			major = opts.Major
+		}
+		if opts.Minor != (Unit{}) {
+			minorUnit = opts.Minor
+		}
+		frac *= dec128.Pow10Uint64[minor-scale]
+		if !ipart.IsZero() || frac == 0 {
+			buf = lang.AppendCardinal(buf, ipart, true)
+			buf = appendUnit(buf, major, ipart.Equal(uint128.One))
+		}
+		if frac > 0 {
+			if !ipart.IsZero() {
+				buf = append(buf, ' ')
+				buf = append(buf, voc.And...)
+				buf = append(buf, ' ')
+			}
+			buf = lang.AppendCardinal(buf, uint128.FromUint64(frac), true)
+			buf = appendUnit(buf, minorUnit, frac == 1)
+		}
+
+	default:
+		frac *= dec128.Pow10Uint64[minor-scale]
+		buf = lang.AppendCardinal(buf, ipart, false)
+		buf = append(buf, ' ')
+		buf = append(buf, voc.And...)
+		buf = append(buf, ' ')
+		buf = appendPadded(buf, frac, int(minor))
+		buf = append(buf, '/')
+		buf = appendPadded(buf, dec128.Pow10Uint64[minor], 1)
+	}
+
+	if opts.Capitalize && start < len(buf) {
+		r, sz := utf8.DecodeRune(buf[start:])
+		if u := unicode.ToUpper(r); u != r && utf8.RuneLen(u) == sz {
+			utf8.EncodeRune(buf[start:], u)
+		}
+	}
+
+	return buf, nil
+}
+
+func appendUnit(buf []byte, u Unit, one bool) []byte {
+	buf = append(buf, ' ')
+	if one {
+		return append(buf, u.One...)
+	}
+	return append(buf, u.Many...)
+}
+
+// appendPadded appends the decimal digits of u left padded with zeros to at least n digits.
+func appendPadded(buf []byte, u uint64, n int) []byte {
+	tmp := [uint128.MaxStrLen64]byte{}
+	i := len(tmp)
+	for u > 0 || len(tmp)-i < n {
+		i--
+		tmp[i] = '0' + byte(u%10)
+		u /= 10
+	}
+	return append(buf, tmp[i:]...)
+}
+
+// groups splits n into digit groups of the given base, least significant first.
+func groups(n uint128.Uint128, base uint64, g []uint64) []uint64 {
+	g = g[:0]
+	for !n.IsZero() {
+		var r uint64
+		n, r, _ = n.QuoRem64(base)
+		g = append(g, r)
+	}
+	return g
+}
@@ -0,0 +1,90 @@
+package words
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jokruger/dec128"
+	"github.com/jokruger/dec128/uint128"
+)
+
+func TestSpell(t *testing.T) {
+	type testCase struct {
+		i    string
+		opts Options
+		s    string
+	}
+
+	en := Options{Capitalize: true}
+	enPoint := Options{Fraction: FractionPoint}
+	enCur := Options{Fraction: FractionCurrency}
+	de := Options{Language: German, Capitalize: true}
+	deCur := Options{Language: German, Fraction: FractionCurrency}
+	es := Options{Language: Spanish}
+	esCur := Options{Language: Spanish, Fraction: FractionCurrency}
+
+	testCases := [...]testCase{
+		{"1234.56", en, "One thousand two hundred thirty-four and 56/100"},
+		{"0", en, "Zero and 00/100"},
+		{"0.07", Options{}, "zero and 07/100"},
+		{"1.999", Options{}, "two and 00/100"},
+		{"-15", Options{}, "minus fifteen and 00/100"},
+		{"1.5", Options{MinorDigits: 3}, "one and 500/1000"},
+		{"100", Options{}, "one hundred and 00/100"},
+		{"1000001", Options{}, "one million one and 00/100"},
+		{"12000000000", Options{}, "twelve billion and 00/100"},
+		{uint128.MaxUint128Str, Options{}, "three hundred forty undecillion two hundred eighty-two decillion three hundred sixty-six nonillion nine hundred twenty octillion nine hundred thirty-eight septillion four hundred sixty-three sextillion four hundred sixty-three quintillion three hundred seventy-four quadrillion six hundred seven trillion four hundred thirty-one billion seven hundred sixty-eight million two hundred eleven thousand four hundred fifty-five and 00/100"},
+		{"1.56", enPoint, "one point five six"},
+		{"1.50", enPoint, "one point five"},
+		{"0.05", enPoint, "zero point zero five"},
+		{"-42", enPoint, "minus forty-two"},
+		{"1234.56", enCur, "one thousand two hundred thirty-four dollars and fifty-six cents"},
+		{"1.01", enCur, "one dollar and one cent"},
+		{"0.5", enCur, "fifty cents"},
+		{"0", enCur, "zero dollars"},
+		{"2", enCur, "two dollars"},
+		{"2", Options{Fraction: FractionCurrency, Major: Unit{"pound", "pounds"}}, "two pounds"},
+		{"1234.56", de, "Eintausendzweihundertvierunddreißig und 56/100"},
+		{"1", Options{Language: German, Fraction: FractionPoint}, "eins"},
+		{"101.5", Options{Language: German, Fraction: FractionPoint}, "einhunderteins Komma fünf"},
+		{"21000", Options{Language: German, Fraction: FractionPoint}, "einundzwanzigtausend"},
+		{"1000000", Options{Language: German, Fraction: FractionPoint}, "eine Million"},
+		{"2001000", Options{Language: German, Fraction: FractionPoint}, "zwei Millionen eintausend"},
+		{"1000000000", Options{Language: German, Fraction: FractionPoint}, "eine Milliarde"},
+		{"1.01", deCur, "ein Euro und ein Cent"},
+		{"31.99", deCur, "einunddreißig Euro und neunundneunzig Cent"},
+		{"1234.56", es, "mil doscientos treinta y cuatro con 56/100"},
+		{"100", Options{Language: Spanish, Fraction: FractionPoint}, "cien"},
+		{"101", Options{Language: Spanish, Fraction: FractionPoint}, "ciento uno"},
+		{"21", Options{Language: Spanish, Fraction: FractionPoint}, "veintiuno"},
+		{"21000", Options{Language: Spanish, Fraction: FractionPoint}, "veintiún mil"},
+		{"1000000", Options{Language: Spanish, Fraction: FractionPoint}, "un millón"},
+		{"1000000000", Options{Language: Spanish, Fraction: FractionPoint}, "mil millones"},
+		{"2000000000000", Options{Language: Spanish, Fraction: FractionPoint}, "dos billones"},
+		{"0.5", Options{Language: Spanish, Fraction: FractionPoint}, "cero coma cinco"},
+		{"21.31", esCur, "veintiún pesos con treinta y un centavos"},
+		{"1", esCur, "un peso"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("TestSpell(%v)", tc.i), func(t *testing.T) {
+			d := dec128.FromString(tc.i)
+			s, err := Spell(d, tc.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s != tc.s {
+				t.Errorf("expected '%s', got: '%s'", tc.s, s)
+			}
+		})
+	}
+}
+
+func TestSpellErrors(t *testing.T) {
+	if _, err := Spell(dec128.NaN(0), Options{}); err == nil {
+		t.Errorf("expected error for NaN, got nil")
+	}
+	if _, err := Spell(dec128.One, Options{MinorDigits: 20}); err == nil {
+		t.Errorf("expected error for MinorDigits out of range, got nil")
+	}
+}
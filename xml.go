@@ -0,0 +1,92 @@
+package dec128
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// MarshalXMLAttr implements the xml.MarshalerAttr interface. The attribute value is the same as MarshalText.
+func (d Dec128) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: d.String()}, nil
+}
+
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface. Leading and trailing white space is ignored, as for xs:decimal.
+func (d *Dec128) UnmarshalXMLAttr(attr xml.Attr) error {
+	return d.UnmarshalText([]byte(strings.TrimSpace(attr.Value)))
+}
+
+// MarshalXML implements the xml.Marshaler interface. The character data of the element is the same as MarshalText.
+func (d Dec128) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(d.String(), start)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface. Leading and trailing white space is ignored, as for xs:decimal.
+func (d *Dec128) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	var s string
+	if err := dec.DecodeElement(&s, &start); err != nil {
+		return err
+	}
+	return d.UnmarshalText([]byte(strings.TrimSpace(s)))
+}
+
+// MarshalXMLAttr implements the xml.MarshalerAttr interface. The attribute value has exactly ColumnScale digits after the decimal point, as Value.
+func (f FixedDec128) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	d := f.rounded()
+	if d.IsNaN() {
+		return xml.Attr{}, d.ErrorDetails()
+	}
+	return xml.Attr{Name: name, Value: d.StringFixed()}, nil
+}
+
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface. The value is rescaled to ColumnScale as by Scan.
+func (f *FixedDec128) UnmarshalXMLAttr(attr xml.Attr) error {
+	return f.Scan(strings.TrimSpace(attr.Value))
+}
+
+// MarshalXML implements the xml.Marshaler interface. The character data has exactly ColumnScale digits after the decimal point, as Value.
+func (f FixedDec128) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	d := f.rounded()
+	if d.IsNaN() {
+		return d.ErrorDetails()
+	}
+	return e.EncodeElement(d.StringFixed(), start)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface. The value is rescaled to ColumnScale as by Scan.
+func (f *FixedDec128) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	var s string
+	if err := dec.DecodeElement(&s, &start); err != nil {
+		return err
+	}
+	return f.Scan(strings.TrimSpace(s))
+}
+
+// MarshalXMLAttr implements the xml.MarshalerAttr interface. An invalid value omits the attribute.
+func (n NullDec128) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if !n.Valid {
+		return xml.Attr{}, nil
+	}
+	return n.Dec128.MarshalXMLAttr(name)
+}
+
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface. An empty value is decoded as an invalid value.
+func (n *NullDec128) UnmarshalXMLAttr(attr xml.Attr) error {
+	return n.UnmarshalText([]byte(strings.TrimSpace(attr.Value)))
+}
+
+// MarshalXML implements the xml.Marshaler interface. An invalid value omits the element.
+func (n NullDec128) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if !n.Valid {
+		return nil
+	}
+	return n.Dec128.MarshalXML(e, start)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface. An empty element is decoded as an invalid value.
+func (n *NullDec128) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	var s string
+	if err := dec.DecodeElement(&s, &start); err != nil {
+		return err
+	}
+	return n.UnmarshalText([]byte(strings.TrimSpace(s)))
+}
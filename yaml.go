@@ -0,0 +1,91 @@
+package dec128
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/jokruger/dec128/state"
+)
+
+// The YAML methods match the Marshaler and obsolete Unmarshaler interfaces of gopkg.in/yaml.v3 (and yaml.v2), so no import is needed.
+// Without a yaml.Node, an unquoted scalar can only be produced from an int64 or float64,
+// so values whose text is not reproduced exactly by one of them are emitted as (quoted) strings.
+
+// MarshalYAML implements the yaml.Marshaler interface.
+// The value is emitted as an unquoted number if yaml formats it exactly as String does, otherwise as a quoted string.
+// Since yaml formats floats in exponent form outside [1e-4, 1e6) and int64 limits integers, quoted values include
+// non-integers with a magnitude of 1e6 or more (e.g. "1234567.5") or below 1e-4 (e.g. "0.00001"), integers outside the int64 range,
+// and values with more digits than a float64 holds (e.g. "0.1234567890123456789").
+// UnmarshalYAML accepts both forms. NaN is emitted as "NaN".
+func (d Dec128) MarshalYAML() (any, error) {
+	if d.state >= state.Error {
+		return NaNStr, nil
+	}
+	return yamlValue(d.String()), nil
+}
+
+// UnmarshalYAML implements the obsolete yaml.Unmarshaler interface, which yaml.v3 still supports.
+// It accepts numbers and strings in the format of FromString. "NaN" is decoded as NaN.
+func (d *Dec128) UnmarshalYAML(unmarshal func(any) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+
+	if s == NaNStr {
+		*d = NaN(state.NaN)
+		return nil
+	}
+
+	return d.UnmarshalText([]byte(s))
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+// The value has exactly ColumnScale digits after the decimal point, as Value, and is emitted as an unquoted number if yaml formats it exactly, otherwise as a quoted string.
+// Since yaml drops trailing zeros of unquoted numbers, values with trailing zeros after the decimal point (e.g. "1.50") are quoted,
+// as are the values quoted by Dec128.MarshalYAML.
+func (f FixedDec128) MarshalYAML() (any, error) {
+	d := f.rounded()
+	if d.IsNaN() {
+		return nil, d.ErrorDetails()
+	}
+	return yamlValue(d.StringFixed()), nil
+}
+
+// UnmarshalYAML implements the obsolete yaml.Unmarshaler interface. The value is rescaled to ColumnScale as by Scan.
+func (f *FixedDec128) UnmarshalYAML(unmarshal func(any) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	return f.Scan(s)
+}
+
+// MarshalYAML implements the yaml.Marshaler interface. An invalid value is emitted as null,
+// a valid value as by Dec128.MarshalYAML (quoted if yaml cannot format it exactly as an unquoted number).
+func (n NullDec128) MarshalYAML() (any, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Dec128.MarshalYAML()
+}
+
+// UnmarshalYAML implements the obsolete yaml.Unmarshaler interface. yaml decodes null as the zero value, which is invalid.
+func (n *NullDec128) UnmarshalYAML(unmarshal func(any) error) error {
+	if err := n.Dec128.UnmarshalYAML(unmarshal); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// yamlValue returns s as an int64 or float64 if yaml formats that number as s, otherwise s itself.
+func yamlValue(s string) any {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil && strconv.FormatInt(i, 10) == s {
+		return i
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsInf(f, 0) && strconv.FormatFloat(f, 'g', -1, 64) == s {
+		return f
+	}
+	return s
+}